@@ -9,4 +9,21 @@ CREATE TABLE `blog_auth`(
     `deleted_on` int(10) unsigned DEFAULT '0' COMMENT '删除时间',
     `is_del` tinyint(3) unsigned DEFAULT '0' COMMENT '是否删除 0为未删除、1为已删除',
     PRIMARY KEY (`id`) USING BTREE
-)ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT = '认证管理';
\ No newline at end of file
+)ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT = '认证管理';
+
+CREATE TABLE `blog_user_identity`(
+    `id` INT(10) UNSIGNED not NULL AUTO_INCREMENT,
+    `auth_id` INT(10) UNSIGNED not NULL DEFAULT '0' COMMENT '认证信息ID',
+    `issuer` VARCHAR(255) DEFAULT '' COMMENT 'OIDC签发者',
+    `subject` VARCHAR(255) DEFAULT '' COMMENT 'OIDC用户标识',
+    `email` VARCHAR(255) DEFAULT '' COMMENT '邮箱',
+    `name` VARCHAR(100) DEFAULT '' COMMENT '名称',
+    `created_on` int(10) unsigned DEFAULT '0' COMMENT '创建时间',
+    `created_by` varchar(100) DEFAULT '' COMMENT '创建人',
+    `modified_on` int(10) unsigned DEFAULT '0' COMMENT '修改时间',
+    `modified_by` varchar(100) DEFAULT '' COMMENT '修改人',
+    `deleted_on` int(10) unsigned DEFAULT '0' COMMENT '删除时间',
+    `is_del` tinyint(3) unsigned DEFAULT '0' COMMENT '是否删除 0为未删除、1为已删除',
+    PRIMARY KEY (`id`) USING BTREE,
+    UNIQUE KEY `uk_issuer_subject` (`issuer`(191), `subject`(191))
+)ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT = '外部身份映射';
@@ -22,6 +22,7 @@ CREATE TABLE `blog_tag` (
 CREATE TABLE `blog_article` (
     `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
     `title` varchar(100) DEFAULT '' COMMENT '文章标题',
+    `slug` varchar(100) DEFAULT '' COMMENT '文章链接中使用的短名称',
     `desc` varchar(255) DEFAULT '' COMMENT '文章简述', 
     `cover_image_url` varchar(255) DEFAULT '' COMMENT '封面图',
     `content` longtext COMMENT '文章内容',
@@ -0,0 +1,29 @@
+// 生成错误码目录文档：go generate ./pkg/errcode
+package main
+
+import (
+	"flag"
+	"io"
+	"log"
+	"os"
+
+	"github.com/ludyyy-lu/goBlogService/pkg/errcode"
+)
+
+func main() {
+	output := flag.String("o", "", "输出文件，为空时输出到标准输出")
+	flag.Parse()
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			log.Fatalf("os.Create err: %v", err)
+		}
+		defer f.Close()
+		w = f
+	}
+	if err := errcode.WriteMarkdown(w); err != nil {
+		log.Fatalf("errcode.WriteMarkdown err: %v", err)
+	}
+}
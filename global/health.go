@@ -0,0 +1,7 @@
+package global
+
+import "github.com/ludyyy-lu/goBlogService/pkg/health"
+
+var (
+	HealthChecker *health.Checker
+)
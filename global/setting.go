@@ -1,15 +1,31 @@
 package global
 
 import (
+	"io"
+	"sync/atomic"
+
 	"github.com/ludyyy-lu/goBlogService/pkg/logger"
 	"github.com/ludyyy-lu/goBlogService/pkg/setting"
 )
 
 var (
-	ServerSetting   *setting.ServerSettingS
-	AppSetting      *setting.AppSettingS
-	DatabaseSetting *setting.DatabaseSettingS
-	Logger          *logger.Logger
-	EmailSetting    *setting.EmailSettingS
-	JWTSetting      *setting.JWTSettingS
+	ServerSetting    *setting.ServerSettingS
+	DatabaseSetting  *setting.DatabaseSettingS
+	Logger           *logger.Logger
+	JWTSetting       *setting.JWTSettingS
+	OIDCSetting      *setting.OIDCSettingS
+	AccessLogSetting *setting.AccessLogSettingS
+	MetricsSetting   *setting.MetricsSettingS
+	TracingSetting   *setting.TracingSettingS
+	HealthSetting    *setting.HealthSettingS
+	// 访问日志使用 combined 格式时的输出目标
+	AccessLogWriter io.Writer
+)
+
+// 支持热加载的配置，热加载时整体替换；读取多个字段时先 Load 一次，避免前后读到不同版本的配置
+var (
+	AppSetting       atomic.Pointer[setting.AppSettingS]
+	EmailSetting     atomic.Pointer[setting.EmailSettingS]
+	RateLimitSetting atomic.Pointer[setting.RateLimitSettingS]
+	LogSetting       atomic.Pointer[setting.LogSettingS]
 )
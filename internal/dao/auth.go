@@ -3,7 +3,18 @@ package dao
 import "github.com/ludyyy-lu/goBlogService/internal/model"
 
 //获取认证
-func (d *Dao) GetAuth(appKey, appSecret string) (model.Auth, error) {
+func (d *Dao) GetAuth(appKey, appSecret string) (a model.Auth, err error) {
+	db, span := d.start("GetAuth")
+	defer func() { end(span, err) }()
 	auth := model.Auth{AppKey:appKey,AppSecret: appSecret}
-	return auth.Get(d.engine)
-}
\ No newline at end of file
+	a, err = auth.Get(db)
+	return a, translate(err)
+}
+
+func (d *Dao) GetAuthByID(id uint32) (a model.Auth, err error) {
+	db, span := d.start("GetAuthByID")
+	defer func() { end(span, err) }()
+	auth := model.Auth{Model: &model.Model{ID: id}}
+	a, err = auth.GetByID(db)
+	return a, translate(err)
+}
@@ -1,11 +1,42 @@
 package dao
 
-import "github.com/jinzhu/gorm"
+import (
+	"context"
+	"errors"
+
+	"github.com/jinzhu/gorm"
+	"github.com/ludyyy-lu/goBlogService/pkg/tracer"
+	"go.opentelemetry.io/otel/trace"
+)
 
 type Dao struct {
 	engine *gorm.DB
+	ctx    context.Context
 }
 
 func New(engine *gorm.DB) *Dao{
 	return &Dao{engine: engine}
-}
\ No newline at end of file
+}
+
+// 返回绑定了请求上下文的 Dao，执行的 SQL 会记录在该上下文的链路中
+func (d *Dao) WithContext(ctx context.Context) *Dao {
+	return &Dao{engine: tracer.WithContext(ctx, d.engine), ctx: ctx}
+}
+
+// 为 Dao 方法创建 span，返回的会话上执行的 SQL 记录为它的子 span；
+// 未绑定请求上下文时不创建新的链路
+func (d *Dao) start(name string) (*gorm.DB, trace.Span) {
+	if d.ctx == nil {
+		return d.engine, trace.SpanFromContext(context.Background())
+	}
+	ctx, span := tracer.Start(d.ctx, "dao."+name)
+	return tracer.WithContext(ctx, d.engine), span
+}
+
+// 记录不存在是正常的查询结果，不标记为失败
+func end(span trace.Span, err error) {
+	if errors.Is(err, ErrNotFound) {
+		err = nil
+	}
+	tracer.End(span, err)
+}
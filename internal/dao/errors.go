@@ -0,0 +1,42 @@
+package dao
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jinzhu/gorm"
+)
+
+var (
+	ErrNotFound  = errors.New("record not found")
+	ErrDuplicate = errors.New("duplicate record")
+	ErrConflict  = errors.New("record conflict")
+)
+
+// MySQL 错误号，见 https://dev.mysql.com/doc/mysql-errors/8.0/en/server-error-reference.html
+const (
+	mysqlErrDupEntry        = 1062
+	mysqlErrRowIsReferenced = 1451
+	mysqlErrNoReferencedRow = 1452
+)
+
+// 将 gorm 和 MySQL 的错误转换为 dao 的错误类型，同时保留原始错误
+func translate(err error) error {
+	if err == nil {
+		return nil
+	}
+	if gorm.IsRecordNotFoundError(err) {
+		return fmt.Errorf("%w: %w", ErrNotFound, err)
+	}
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		switch mysqlErr.Number {
+		case mysqlErrDupEntry:
+			return fmt.Errorf("%w: %w", ErrDuplicate, err)
+		case mysqlErrRowIsReferenced, mysqlErrNoReferencedRow:
+			return fmt.Errorf("%w: %w", ErrConflict, err)
+		}
+	}
+	return err
+}
@@ -5,27 +5,61 @@ import (
 	"github.com/ludyyy-lu/goBlogService/pkg/app"
 )
 
-func (d *Dao) CountTag(name string, state uint8) (int, error) {
+func (d *Dao) CountTag(name string, state uint8) (count int, err error) {
+	db, span := d.start("CountTag")
+	defer func() { end(span, err) }()
 	tag := model.Tag{Name: name, State: state}
-	return tag.Count(d.engine)
+	count, err = tag.Count(db)
+	return count, translate(err)
 }
 
-func (d *Dao) GetTagList(name string, state uint8, page, pagesize int) ([]*model.Tag, error) {
+func (d *Dao) GetTagList(name string, state uint8, page, pagesize int) (tags []*model.Tag, err error) {
+	db, span := d.start("GetTagList")
+	defer func() { end(span, err) }()
 	tag := model.Tag{Name: name, State: state}
 	pageOffset := app.GetPageOffset(page, pagesize)
-	return tag.List(d.engine, pageOffset, pagesize)
+	tags, err = tag.List(db, pageOffset, pagesize)
+	return tags, translate(err)
 }
 
-func (d *Dao) CreateTag(name string, state uint8, createdBy string) error {
+func (d *Dao) CountTagByIDs(ids []uint32) (count int, err error) {
+	db, span := d.start("CountTagByIDs")
+	defer func() { end(span, err) }()
+	tag := model.Tag{}
+	count, err = tag.CountByIDs(db, ids)
+	return count, translate(err)
+}
+
+func (d *Dao) GetTag(id uint32) (t model.Tag, err error) {
+	db, span := d.start("GetTag")
+	defer func() { end(span, err) }()
+	tag := model.Tag{Model: &model.Model{ID: id}}
+	t, err = tag.Get(db)
+	return t, translate(err)
+}
+
+func (d *Dao) GetTagByName(name string) (t model.Tag, err error) {
+	db, span := d.start("GetTagByName")
+	defer func() { end(span, err) }()
+	tag := model.Tag{Name: name}
+	t, err = tag.Get(db)
+	return t, translate(err)
+}
+
+func (d *Dao) CreateTag(name string, state uint8, createdBy string) (err error) {
+	db, span := d.start("CreateTag")
+	defer func() { end(span, err) }()
 	tag := model.Tag{
 		Name:  name,
 		State: state,
 		Model: &model.Model{CreatedBy: createdBy},
 	}
-	return tag.Create(d.engine)
+	return translate(tag.Create(db))
 }
 
-func (d *Dao) UpdateTag(id uint32, name string, state uint8, modifiedBy string) error {
+func (d *Dao) UpdateTag(id uint32, name string, state uint8, modifiedBy string) (err error) {
+	db, span := d.start("UpdateTag")
+	defer func() { end(span, err) }()
 	tag := model.Tag{
 		Model: &model.Model{ID: id},
 	}
@@ -36,10 +70,12 @@ func (d *Dao) UpdateTag(id uint32, name string, state uint8, modifiedBy string)
 	if name != "" {
 		vals["name"] = name
 	}
-	return tag.Update(d.engine, vals)
+	return translate(tag.Update(db, vals))
 }
 
-func (d *Dao) DeleteTag(id uint32) error {
+func (d *Dao) DeleteTag(id uint32) (err error) {
+	db, span := d.start("DeleteTag")
+	defer func() { end(span, err) }()
 	tag := model.Tag{Model: &model.Model{ID: id}}
-	return tag.Delete(d.engine)
+	return translate(tag.Delete(db))
 }
@@ -0,0 +1,51 @@
+package dao
+
+import "github.com/ludyyy-lu/goBlogService/internal/model"
+
+func (d *Dao) GetUserIdentity(issuer, subject string) (i model.UserIdentity, err error) {
+	db, span := d.start("GetUserIdentity")
+	defer func() { end(span, err) }()
+	identity := model.UserIdentity{Issuer: issuer, Subject: subject}
+	i, err = identity.Get(db)
+	return i, translate(err)
+}
+
+func (d *Dao) UpdateUserIdentity(id uint32, email, name string) (err error) {
+	db, span := d.start("UpdateUserIdentity")
+	defer func() { end(span, err) }()
+	identity := model.UserIdentity{Model: &model.Model{ID: id}}
+	return translate(identity.Update(db, map[string]any{
+		"email":       email,
+		"name":        name,
+		"modified_by": "oidc",
+	}))
+}
+
+// 首次登录时在同一个事务中创建认证信息和身份映射
+func (d *Dao) CreateUserIdentity(issuer, subject, email, name, appKey, appSecret string) (_ model.Auth, err error) {
+	db, span := d.start("CreateUserIdentity")
+	defer func() { end(span, err) }()
+	auth := model.Auth{
+		Model:     &model.Model{CreatedBy: "oidc"},
+		AppKey:    appKey,
+		AppSecret: appSecret,
+	}
+	tx := db.Begin()
+	if err := auth.Create(tx); err != nil {
+		tx.Rollback()
+		return auth, translate(err)
+	}
+	identity := model.UserIdentity{
+		Model:   &model.Model{CreatedBy: "oidc"},
+		AuthID:  auth.ID,
+		Issuer:  issuer,
+		Subject: subject,
+		Email:   email,
+		Name:    name,
+	}
+	if err := identity.Create(tx); err != nil {
+		tx.Rollback()
+		return auth, translate(err)
+	}
+	return auth, translate(tx.Commit().Error)
+}
@@ -2,48 +2,251 @@ package middleware
 
 import (
 	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/ludyyy-lu/goBlogService/global"
 	"github.com/ludyyy-lu/goBlogService/pkg/logger"
+	"github.com/ludyyy-lu/goBlogService/pkg/redact"
+	"github.com/ludyyy-lu/goBlogService/pkg/setting"
 )
 
+const defaultMaxBodySize = 4096
+
+// 未配置时允许记录内容的 Content-Type，其余类型（如图片、文件）只记录大小
+var defaultBodyContentTypes = []string{
+	"application/json",
+	"application/x-www-form-urlencoded",
+	"text/*",
+}
+
 //访问日志
 type AccessLogWriter struct {
 	gin.ResponseWriter
-	body *bytes.Buffer
+	body      *bytes.Buffer
+	limit     int
+	truncated bool
 }
 
-func (w AccessLogWriter) Write(p []byte) (int,error){
-	if n,err := w.body.Write(p);err != nil {
-		return n,err
-	}
+func (w *AccessLogWriter) Write(p []byte) (int, error) {
+	w.capture(p)
 	return w.ResponseWriter.Write(p)
 }
 
-func AccessLog() gin.HandlerFunc{
+func (w *AccessLogWriter) WriteString(s string) (int, error) {
+	w.capture([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+// 只缓存不超过上限的部分响应内容
+func (w *AccessLogWriter) capture(p []byte) {
+	if remain := w.limit - w.body.Len(); remain < len(p) {
+		w.truncated = true
+		p = p[:max(remain, 0)]
+	}
+	w.body.Write(p)
+}
+
+// 边读边缓存请求体，处理函数读取请求体时不受影响
+type bodyCapture struct {
+	io.ReadCloser
+	body      *bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (b *bodyCapture) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	chunk := p[:n]
+	if remain := b.limit - b.body.Len(); remain < n {
+		b.truncated = true
+		chunk = chunk[:max(remain, 0)]
+	}
+	b.body.Write(chunk)
+	return n, err
+}
+
+func AccessLog() gin.HandlerFunc {
+	cfg := global.AccessLogSetting
+	//未读取到 AccessLog 配置时全部使用默认值
+	if cfg == nil {
+		cfg = &setting.AccessLogSettingS{}
+	}
+	redactor, err := redact.New(redact.Rules{
+		Fields:      cfg.RedactFields,
+		QueryParams: cfg.RedactQueryParams,
+		JSONPaths:   cfg.RedactJSONPaths,
+		Headers:     cfg.RedactHeaders,
+		Patterns:    cfg.RedactPatterns,
+	})
+	if err != nil {
+		global.Logger.Errorf("redact.New err: %v", err)
+	}
+	maxBodySize := cfg.MaxBodySize
+	if maxBodySize <= 0 {
+		maxBodySize = defaultMaxBodySize
+	}
+	contentTypes := cfg.BodyContentTypes
+	if len(contentTypes) == 0 {
+		contentTypes = defaultBodyContentTypes
+	}
+
 	return func(c *gin.Context) {
 		bodyWriter := &AccessLogWriter{
-			body: bytes.NewBufferString(""),
+			body:           bytes.NewBufferString(""),
+			limit:          maxBodySize,
 			ResponseWriter: c.Writer,
 		}
 		c.Writer = bodyWriter
 
-		beginTime := time.Now().Unix()
+		var reqBody *bodyCapture
+		if c.Request.Body != nil && loggableContentType(c.ContentType(), contentTypes) {
+			reqBody = &bodyCapture{ReadCloser: c.Request.Body, body: &bytes.Buffer{}, limit: maxBodySize}
+			c.Request.Body = reqBody
+		}
+
+		beginTime := time.Now()
 		c.Next()
-		endTime := time.Now().Unix()
+		endTime := time.Now()
+		latency := endTime.Sub(beginTime)
+
+		route := c.FullPath()
+		if route == "" {
+			route = c.Request.URL.Path
+		}
+		requestSize := c.Request.ContentLength
+		if reqBody != nil && !reqBody.truncated {
+			requestSize = int64(reqBody.body.Len())
+		}
+
+		if cfg.Format == "combined" && global.AccessLogWriter != nil {
+			line := combinedLogLine(c, beginTime, bodyWriter.Status(), bodyWriter.Size(), redactor)
+			if _, err := io.WriteString(global.AccessLogWriter, line); err != nil {
+				global.Logger.Errorf("write access log err: %v", err)
+			}
+			return
+		}
 
 		fields := logger.Fields{
-			"request": c.Request.PostForm.Encode(),
-			"response": bodyWriter.body.String(),
+			"route":           route,
+			"query":           redactor.Query(c.Request.URL.Query()).Encode(),
+			"client_ip":       c.ClientIP(),
+			"user_agent":      c.Request.UserAgent(),
+			"status_code":     bodyWriter.Status(),
+			"latency_ms":      float64(latency.Microseconds()) / 1000,
+			"request_size":    requestSize,
+			"response_size":   bodyWriter.Size(),
+			"begin_time":      beginTime.UnixMilli(),
+			"end_time":        endTime.UnixMilli(),
+			"request":         requestPayload(c, reqBody, redactor),
+			"request_headers": redactor.Header(c.Request.Header),
+			"response":        payload(bodyWriter.Header().Get("Content-Type"), bodyWriter.body.Bytes(), bodyWriter.truncated, bodyWriter.Size(), contentTypes, redactor),
 		}
-		s := "access log: method: %s, status_code: %d, " + "begin_time: %d, end_time: %d"
-		global.Logger.WithFields(fields).Infof(s,
+		if appKey := c.GetString("app_key"); appKey != "" {
+			fields["principal"] = appKey
+		}
+		s := "access log: method: %s, route: %s, status_code: %d, latency: %.3fms"
+		global.Logger.WithComponent("access").WithContext(c.Request.Context()).WithFields(fields).Infof(s,
 			c.Request.Method,
+			route,
 			bodyWriter.Status(),
-			beginTime,
-			endTime,
+			float64(latency.Microseconds())/1000,
 		)
 	}
-}
\ No newline at end of file
+}
+
+// Apache/NCSA combined 格式：
+// %h %l %u %t "%r" %>s %b "%{Referer}i" "%{User-agent}i"
+func combinedLogLine(c *gin.Context, beginTime time.Time, status, size int, redactor *redact.Redactor) string {
+	user := c.GetString("app_key")
+	if user == "" {
+		user = "-"
+	}
+	uri := c.Request.URL.Path
+	if c.Request.URL.RawQuery != "" {
+		uri += "?" + redactor.Query(c.Request.URL.Query()).Encode()
+	}
+	bytesSent := "-"
+	if size > 0 {
+		bytesSent = fmt.Sprint(size)
+	}
+	referer := c.Request.Referer()
+	if referer == "" {
+		referer = "-"
+	}
+	return fmt.Sprintf("%s - %s [%s] \"%s %s %s\" %d %s %q %q\n",
+		c.ClientIP(),
+		user,
+		beginTime.Format("02/Jan/2006:15:04:05 -0700"),
+		c.Request.Method,
+		uri,
+		c.Request.Proto,
+		status,
+		bytesSent,
+		referer,
+		c.Request.UserAgent(),
+	)
+}
+
+// 表单优先使用已解析的字段，multipart 只记录普通字段，不记录文件内容
+func requestPayload(c *gin.Context, reqBody *bodyCapture, redactor *redact.Redactor) string {
+	contentType := c.ContentType()
+	switch {
+	case contentType == "multipart/form-data":
+		if c.Request.MultipartForm == nil {
+			return fmt.Sprintf("[multipart body omitted, %d bytes]", c.Request.ContentLength)
+		}
+		return redactor.Values(c.Request.MultipartForm.Value).Encode()
+	case contentType == "application/x-www-form-urlencoded" && c.Request.PostForm != nil:
+		return redactor.Values(c.Request.PostForm).Encode()
+	case reqBody == nil:
+		if c.Request.ContentLength > 0 {
+			return fmt.Sprintf("[%s body omitted, %d bytes]", contentType, c.Request.ContentLength)
+		}
+		return ""
+	}
+	if contentType == "application/x-www-form-urlencoded" && !reqBody.truncated {
+		if values, err := url.ParseQuery(reqBody.body.String()); err == nil {
+			return redactor.Values(values).Encode()
+		}
+	}
+	return payload(contentType, reqBody.body.Bytes(), reqBody.truncated, int(c.Request.ContentLength), nil, redactor)
+}
+
+// 按内容类型脱敏，截断的 JSON 无法解析，按文本规则处理
+func payload(contentType string, body []byte, truncated bool, size int, contentTypes []string, redactor *redact.Redactor) string {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if contentTypes != nil && len(body) > 0 && !loggableContentType(mediaType, contentTypes) {
+		return fmt.Sprintf("[%s body omitted, %d bytes]", mediaType, size)
+	}
+	var s string
+	if mediaType == "application/json" && !truncated {
+		s = redactor.JSON(body)
+	} else {
+		s = redactor.Text(string(body))
+	}
+	if truncated {
+		s += "...(truncated)"
+	}
+	return s
+}
+
+func loggableContentType(mediaType string, contentTypes []string) bool {
+	for _, t := range contentTypes {
+		if prefix, ok := strings.CutSuffix(t, "*"); ok {
+			if strings.HasPrefix(mediaType, prefix) {
+				return true
+			}
+			continue
+		}
+		if mediaType == t {
+			return true
+		}
+	}
+	return false
+}
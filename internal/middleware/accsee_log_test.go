@@ -0,0 +1,211 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ludyyy-lu/goBlogService/global"
+	"github.com/ludyyy-lu/goBlogService/pkg/logger"
+	"github.com/ludyyy-lu/goBlogService/pkg/redact"
+	"github.com/ludyyy-lu/goBlogService/pkg/setting"
+)
+
+// 请求体和响应体超过 MaxBodySize 时截断记录，截断的 JSON 按文本规则脱敏
+func TestAccessLogRedactsTruncatedBodies(t *testing.T) {
+	var buf bytes.Buffer
+	global.Logger = logger.NewLogger(logger.NewSink(&buf, logger.FormatJSON))
+	prev := global.AccessLogSetting
+	global.AccessLogSetting = &setting.AccessLogSettingS{MaxBodySize: 32}
+	t.Cleanup(func() { global.AccessLogSetting = prev })
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(AccessLog())
+	r.POST("/auth", func(c *gin.Context) {
+		var body map[string]any
+		_ = c.ShouldBindJSON(&body)
+		c.JSON(http.StatusOK, gin.H{"access_token": "response-secret-value", "expire": 7200, "pad": strings.Repeat("x", 64)})
+	})
+	reqBody := `{"app_key":"k","password":"request-secret-value","pad":"` + strings.Repeat("y", 64) + `"}`
+	req := httptest.NewRequest(http.MethodPost, "/auth?token=query-secret", strings.NewReader(reqBody))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer header-secret")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	var entry struct {
+		Query          string            `json:"query"`
+		Request        string            `json:"request"`
+		Response       string            `json:"response"`
+		RequestSize    int               `json:"request_size"`
+		RequestHeaders map[string]string `json:"request_headers"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("access log is not JSON: %v\n%s", err, buf.String())
+	}
+	for _, secret := range []string{"request-secret", "response-secret", "query-secret", "header-secret"} {
+		if strings.Contains(buf.String(), secret) {
+			t.Errorf("access log contains %q: %s", secret, buf.String())
+		}
+	}
+	for name, s := range map[string]string{"request": entry.Request, "response": entry.Response} {
+		if !strings.HasSuffix(s, "...(truncated)") || !strings.Contains(s, redact.Mask) {
+			t.Errorf("%s = %q, want a masked, truncated body", name, s)
+		}
+	}
+	if entry.RequestSize != len(reqBody) {
+		t.Errorf("request_size = %d, want the full %d bytes", entry.RequestSize, len(reqBody))
+	}
+	if entry.RequestHeaders["Authorization"] != redact.Mask {
+		t.Errorf("Authorization = %q, want masked", entry.RequestHeaders["Authorization"])
+	}
+}
+
+func setAccessLog(t *testing.T, cfg *setting.AccessLogSettingS, writer io.Writer) {
+	t.Helper()
+	prevSetting, prevWriter := global.AccessLogSetting, global.AccessLogWriter
+	global.AccessLogSetting, global.AccessLogWriter = cfg, writer
+	t.Cleanup(func() { global.AccessLogSetting, global.AccessLogWriter = prevSetting, prevWriter })
+}
+
+func TestAccessLogFields(t *testing.T) {
+	var buf bytes.Buffer
+	global.Logger = logger.NewLogger(logger.NewSink(&buf, logger.FormatJSON))
+	setAccessLog(t, &setting.AccessLogSettingS{}, nil)
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(func(c *gin.Context) { c.Set("app_key", "blog-admin") }, AccessLog())
+	r.GET("/api/v1/tags/:id", func(c *gin.Context) { c.String(http.StatusOK, "hello") })
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/tags/7?page=2&token=secret", nil)
+	req.RemoteAddr = "192.0.2.10:5678"
+	req.Header.Set("User-Agent", "curl/8.0")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("access log is not JSON: %v\n%s", err, buf.String())
+	}
+	want := map[string]any{
+		"route":         "/api/v1/tags/:id",
+		"client_ip":     "192.0.2.10",
+		"user_agent":    "curl/8.0",
+		"status_code":   float64(http.StatusOK),
+		"response_size": float64(len("hello")),
+		"principal":     "blog-admin",
+	}
+	for key, value := range want {
+		if entry[key] != value {
+			t.Errorf("%s = %v, want %v", key, entry[key], value)
+		}
+	}
+	if query, _ := entry["query"].(string); !strings.Contains(query, "page=2") || strings.Contains(query, "secret") {
+		t.Errorf("query = %q, want page kept and token masked", query)
+	}
+	for _, key := range []string{"latency_ms", "request_size", "begin_time", "end_time"} {
+		if _, ok := entry[key].(float64); !ok {
+			t.Errorf("%s = %v, want a number", key, entry[key])
+		}
+	}
+}
+
+func TestAccessLogClientIP(t *testing.T) {
+	tests := []struct {
+		name    string
+		proxies []string
+		want    string
+	}{
+		{"untrusted proxy header ignored", nil, "10.0.0.1"},
+		{"trusted proxy header used", []string{"10.0.0.0/8"}, "203.0.113.7"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			global.Logger = logger.NewLogger(logger.NewSink(&buf, logger.FormatJSON))
+			setAccessLog(t, &setting.AccessLogSettingS{}, nil)
+			gin.SetMode(gin.TestMode)
+
+			r := gin.New()
+			if err := r.SetTrustedProxies(tt.proxies); err != nil {
+				t.Fatal(err)
+			}
+			r.Use(AccessLog())
+			r.GET("/", func(c *gin.Context) {})
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = "10.0.0.1:1234"
+			req.Header.Set("X-Forwarded-For", "203.0.113.7")
+			r.ServeHTTP(httptest.NewRecorder(), req)
+
+			var entry struct {
+				ClientIP string `json:"client_ip"`
+			}
+			if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+				t.Fatal(err)
+			}
+			if entry.ClientIP != tt.want {
+				t.Errorf("client_ip = %q, want %q", entry.ClientIP, tt.want)
+			}
+		})
+	}
+}
+
+func TestAccessLogCombined(t *testing.T) {
+	var logs, out bytes.Buffer
+	global.Logger = logger.NewLogger(logger.NewSink(&logs, logger.FormatJSON))
+	setAccessLog(t, &setting.AccessLogSettingS{Format: "combined"}, &out)
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		if c.Query("auth") != "" {
+			c.Set("app_key", "blog-admin")
+		}
+	}, AccessLog())
+	r.GET("/api/v1/tags", func(c *gin.Context) { c.String(http.StatusOK, "hello") })
+	r.DELETE("/api/v1/tags/:id", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+	line := regexp.MustCompile(`^192\.0\.2\.10 - (\S+) \[\d{2}/\w{3}/\d{4}:\d{2}:\d{2}:\d{2} [+-]\d{4}\] "(.+)" (\d{3}) (\S+) "(.*)" "(.*)"\n$`)
+	tests := []struct {
+		name    string
+		method  string
+		target  string
+		referer string
+		want    []string
+	}{
+		{"full line", http.MethodGet, "/api/v1/tags?auth=1&token=secret", "https://example.com/", []string{"blog-admin", "GET /api/v1/tags?auth=1&token=" + url.QueryEscape(redact.Mask) + " HTTP/1.1", "200", "5", "https://example.com/", "curl/8.0"}},
+		{"anonymous without body or referer", http.MethodDelete, "/api/v1/tags/7", "", []string{"-", "DELETE /api/v1/tags/7 HTTP/1.1", "204", "-", "-", "curl/8.0"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out.Reset()
+			req := httptest.NewRequest(tt.method, tt.target, nil)
+			req.RemoteAddr = "192.0.2.10:5678"
+			req.Header.Set("User-Agent", "curl/8.0")
+			if tt.referer != "" {
+				req.Header.Set("Referer", tt.referer)
+			}
+			r.ServeHTTP(httptest.NewRecorder(), req)
+
+			m := line.FindStringSubmatch(out.String())
+			if m == nil {
+				t.Fatalf("line %q is not in combined format", out.String())
+			}
+			for i, want := range tt.want {
+				if m[i+1] != want {
+					t.Errorf("field %d = %q, want %q", i+1, m[i+1], want)
+				}
+			}
+		})
+	}
+	//combined 格式只写入访问日志文件，不再写结构化日志
+	if logs.Len() != 0 {
+		t.Errorf("structured log written in combined format: %s", logs.String())
+	}
+}
@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ludyyy-lu/goBlogService/global"
+	"github.com/ludyyy-lu/goBlogService/pkg/app"
+	"github.com/ludyyy-lu/goBlogService/pkg/errcode"
+	"github.com/ludyyy-lu/goBlogService/pkg/utils"
+)
+
+// 只允许 JWT.AdminAppKeys 中的 app_key 访问，需放在 JWT 中间件之后
+func Admin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		//token 中保存的是 app_key 的 MD5
+		appKey := c.GetString("app_key")
+		for _, k := range global.JWTSetting.AdminAppKeys {
+			if appKey != "" && utils.EncodeMD5(k) == appKey {
+				c.Next()
+				return
+			}
+		}
+		global.Logger.WithContext(c.Request.Context()).Warnf("admin access denied: %s %s", c.Request.Method, c.Request.URL.Path)
+		response := app.NewResponse(c)
+		response.ToErrorResponse(errcode.Forbidden)
+		c.Abort()
+	}
+}
@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ludyyy-lu/goBlogService/global"
+	"github.com/ludyyy-lu/goBlogService/pkg/logger"
+	"github.com/ludyyy-lu/goBlogService/pkg/setting"
+	"github.com/ludyyy-lu/goBlogService/pkg/utils"
+)
+
+func TestAdmin(t *testing.T) {
+	global.Logger = logger.NewLogger()
+	global.JWTSetting = &setting.JWTSettingS{AdminAppKeys: []string{"ops"}}
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name   string
+		appKey string
+		want   int
+	}{
+		{"admin app_key", utils.EncodeMD5("ops"), http.StatusOK},
+		{"other app_key", utils.EncodeMD5("editor"), http.StatusForbidden},
+		{"plain admin app_key", "ops", http.StatusForbidden},
+		{"no app_key", "", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.PUT("/admin/log/level", func(c *gin.Context) {
+				if tt.appKey != "" {
+					c.Set("app_key", tt.appKey)
+				}
+			}, Admin(), func(c *gin.Context) { c.Status(http.StatusOK) })
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/admin/log/level", nil))
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d", w.Code, tt.want)
+			}
+		})
+	}
+}
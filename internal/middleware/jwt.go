@@ -5,6 +5,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/ludyyy-lu/goBlogService/pkg/app"
 	"github.com/ludyyy-lu/goBlogService/pkg/errcode"
+	"github.com/ludyyy-lu/goBlogService/pkg/logger"
 )
 
 func JWT() gin.HandlerFunc{
@@ -21,8 +22,13 @@ func JWT() gin.HandlerFunc{
 		if token == "" {
 			ecode = errcode.InvalidParams
 		} else {
-			_,err := app.ParseToken(token)
-			if err != nil {
+			claims,err := app.ParseToken(token)
+			if err == nil {
+				c.Set("app_key", claims.AppKey)
+				if info, ok := logger.FromContext(c.Request.Context()); ok {
+					info.Principal = claims.AppKey
+				}
+			} else {
 				switch err.(*jwt.ValidationError).Errors{
 				case jwt.ValidationErrorExpired:
 					ecode = errcode.UnauthorizedTokenTimeout
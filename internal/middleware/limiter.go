@@ -1,24 +1,79 @@
 package middleware
 
 import (
+	"math"
+	"strconv"
+	"time"
+
 	"github.com/gin-gonic/gin"
+	"github.com/ludyyy-lu/goBlogService/global"
 	"github.com/ludyyy-lu/goBlogService/pkg/app"
 	"github.com/ludyyy-lu/goBlogService/pkg/errcode"
 	"github.com/ludyyy-lu/goBlogService/pkg/limiter"
+	"github.com/ludyyy-lu/goBlogService/pkg/logger"
+	"github.com/ludyyy-lu/goBlogService/pkg/metrics"
 )
 
 func RateLimiter(l limiter.LimiterIface) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		key := l.Key(c)
-		if bucket,ok := l.GetBucket(key); ok {
+		//热加载的限流器每个请求只取一次，避免 Key 与 GetBucket 落在替换前后两个不同的限流器上
+		current := l
+		if d, ok := l.(*limiter.DynamicLimiter); ok {
+			current = d.Load()
+		}
+		key := current.Key(c)
+		if bucket, ok := current.GetBucket(key); ok {
 			count := bucket.TakeAvailable(1)
+			retryAfter := setRateLimitHeaders(c, bucket)
 			if count == 0 {
+				global.Logger.WithComponent("limiter").WithContext(c.Request.Context()).WithFields(logger.Fields{
+					"limiter_key": key,
+					"limit":       bucket.Capacity(),
+					"retry_after": retryAfter,
+					"client_ip":   c.ClientIP(),
+				}).Warnf("rate limit exceeded: %s %s", c.Request.Method, c.Request.URL.Path)
+				route := c.FullPath()
+				if route == "" {
+					route = unmatchedRoute
+				}
+				metrics.RateLimitRejectionsTotal.WithLabelValues(c.Request.Method, route).Inc()
+				c.Header("Retry-After", strconv.FormatInt(retryAfter, 10))
 				response := app.NewResponse(c)
-				response.ToErrorResponse(errcode.TooManyRequests)
+				response.ToErrorResponse(errcode.TooManyRequests.WithArgs(retryAfter))
 				c.Abort()
 				return
 			}
 		}
 		c.Next()
 	}
-}
\ No newline at end of file
+}
+
+// 根据令牌桶状态设置限流响应头，返回距离下一个令牌可用的秒数。
+// X-RateLimit-Reset 为令牌桶恢复满额时的 Unix 时间戳（秒）。
+func setRateLimitHeaders(c *gin.Context, bucket limiter.Bucket) int64 {
+	limit := bucket.Capacity()
+	remaining := max(bucket.Available(), 0)
+	retryAfter, resetAfter := bucketTiming(bucket, limit, remaining)
+	c.Header("X-RateLimit-Limit", strconv.FormatInt(limit, 10))
+	c.Header("X-RateLimit-Remaining", strconv.FormatInt(remaining, 10))
+	c.Header("X-RateLimit-Reset", strconv.FormatInt(time.Now().Unix()+resetAfter, 10))
+	return retryAfter
+}
+
+// 令牌桶能给出时间时（共享存储的结果或本地令牌桶的补充周期）直接使用，否则按令牌的补充速率估算，单位为秒
+func bucketTiming(bucket limiter.Bucket, limit, remaining int64) (retryAfter, resetAfter int64) {
+	if b, ok := bucket.(limiter.TimedBucket); ok {
+		if retry, reset, ok := b.Timing(); ok {
+			return int64(math.Ceil(retry.Seconds())), int64(math.Ceil(reset.Seconds()))
+		}
+	}
+	rate := bucket.Rate()
+	if rate <= 0 {
+		return 0, 0
+	}
+	resetAfter = int64(math.Ceil(float64(limit-remaining) / rate))
+	if remaining < 1 {
+		retryAfter = int64(math.Ceil(1 / rate))
+	}
+	return retryAfter, resetAfter
+}
@@ -0,0 +1,142 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ludyyy-lu/goBlogService/global"
+	"github.com/ludyyy-lu/goBlogService/pkg/limiter"
+	"github.com/ludyyy-lu/goBlogService/pkg/logger"
+)
+
+type stubBucket struct {
+	available, capacity int64
+	rate                float64
+}
+
+func (b stubBucket) TakeAvailable(int64) int64 { return 0 }
+func (b stubBucket) Available() int64          { return b.available }
+func (b stubBucket) Capacity() int64           { return b.capacity }
+func (b stubBucket) Rate() float64             { return b.rate }
+
+type stubTimedBucket struct {
+	stubBucket
+	retry, reset time.Duration
+	ok           bool
+}
+
+func (b stubTimedBucket) Timing() (time.Duration, time.Duration, bool) {
+	return b.retry, b.reset, b.ok
+}
+
+func TestSetRateLimitHeaders(t *testing.T) {
+	tests := []struct {
+		name      string
+		bucket    limiter.Bucket
+		wantRetry int64
+		wantReset int64
+	}{
+		{
+			name:      "estimated from rate",
+			bucket:    stubBucket{available: 0, capacity: 10, rate: 0.5},
+			wantRetry: 2,
+			wantReset: 20,
+		},
+		{
+			name: "timing from store",
+			bucket: stubTimedBucket{
+				stubBucket: stubBucket{available: 0, capacity: 10, rate: 0.5},
+				retry:      1500 * time.Millisecond,
+				reset:      4 * time.Second,
+				ok:         true,
+			},
+			wantRetry: 2,
+			wantReset: 4,
+		},
+		{
+			name:      "local bucket waits for the next fill",
+			bucket:    emptyLocalBucket(t, limiter.LimiterBucketRule{Key: "*", FillInterval: time.Minute, Capacity: 60, Quantum: 60}),
+			wantRetry: 60,
+			wantReset: 60,
+		},
+		{
+			name: "store unavailable",
+			bucket: stubTimedBucket{
+				stubBucket: stubBucket{available: 5, capacity: 10, rate: 1},
+			},
+			wantRetry: 0,
+			wantReset: 5,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			retry := setRateLimitHeaders(c, tt.bucket)
+			if retry != tt.wantRetry {
+				t.Errorf("retry after = %d, want %d", retry, tt.wantRetry)
+			}
+			reset, _ := strconv.ParseInt(w.Header().Get("X-RateLimit-Reset"), 10, 64)
+			if got := reset - time.Now().Unix(); got < tt.wantReset-1 || got > tt.wantReset {
+				t.Errorf("reset after = %d, want %d", got, tt.wantReset)
+			}
+		})
+	}
+}
+
+// 取出本地令牌桶并用完其中的令牌
+func emptyLocalBucket(t *testing.T, rule limiter.LimiterBucketRule) limiter.Bucket {
+	t.Helper()
+	l := limiter.NewMethodLimiter().AddBuckets(rule)
+	bucket, ok := l.GetBucket("GET /")
+	if !ok {
+		t.Fatal("no bucket for GET /")
+	}
+	bucket.TakeAvailable(rule.Capacity)
+	return bucket
+}
+
+// Key 被调用时模拟配置热加载，将限流器替换为 next
+type reloadingLimiter struct {
+	dynamic *limiter.DynamicLimiter
+	next    limiter.LimiterIface
+	bucket  limiter.Bucket
+}
+
+func (l *reloadingLimiter) Key(c *gin.Context) string {
+	if l.next != nil {
+		l.dynamic.Store(l.next)
+	}
+	return "GET /auth"
+}
+
+func (l *reloadingLimiter) GetBucket(string) (limiter.Bucket, bool) {
+	return l.bucket, l.bucket != nil
+}
+
+func (l *reloadingLimiter) AddBuckets(...limiter.LimiterBucketRule) limiter.LimiterIface { return l }
+
+func TestRateLimiterLoadsDynamicLimiterOnce(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	global.Logger = logger.NewLogger()
+	current := &reloadingLimiter{
+		bucket: stubBucket{available: 0, capacity: 1, rate: 1},
+		next:   &reloadingLimiter{},
+	}
+	dynamic := limiter.NewDynamicLimiter(current)
+	current.dynamic = dynamic
+
+	r := gin.New()
+	r.Use(RateLimiter(dynamic))
+	r.GET("/auth", func(c *gin.Context) {})
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/auth", nil))
+	//请求中途替换的限流器没有令牌桶，若 GetBucket 重新取了限流器请求就会被放行
+	if w.Code != http.StatusTooManyRequests {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusTooManyRequests)
+	}
+}
@@ -0,0 +1,118 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ludyyy-lu/goBlogService/global"
+	"github.com/ludyyy-lu/goBlogService/pkg/logger"
+	"github.com/ludyyy-lu/goBlogService/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// 指标是全局的，用例只比较请求前后的增量
+func TestMetricsLabels(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Metrics())
+	r.GET("/api/v1/tags/:id", func(c *gin.Context) {
+		if c.Param("id") == "0" {
+			c.Status(http.StatusInternalServerError)
+			return
+		}
+		c.Status(http.StatusOK)
+	})
+	tests := []struct {
+		name   string
+		target string
+		route  string
+		status string
+	}{
+		{"route template", "/api/v1/tags/7", "/api/v1/tags/:id", "200"},
+		{"same template for other ids", "/api/v1/tags/8", "/api/v1/tags/:id", "200"},
+		{"server error", "/api/v1/tags/0", "/api/v1/tags/:id", "500"},
+		{"unmatched route", "/wp-login.php", unmatchedRoute, "404"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			counter := metrics.HTTPRequestsTotal.WithLabelValues("GET", tt.route, tt.status)
+			before := testutil.ToFloat64(counter)
+			r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", tt.target, nil))
+			if got := testutil.ToFloat64(counter) - before; got != 1 {
+				t.Errorf("http_requests_total{route=%q,status=%q} increased by %v, want 1", tt.route, tt.status, got)
+			}
+		})
+	}
+	//原始路径不能出现在标签中
+	body := scrape(t)
+	for _, path := range []string{"/api/v1/tags/7", "/wp-login.php"} {
+		if strings.Contains(body, `route="`+path+`"`) {
+			t.Errorf("metrics contain the raw path %s as a route label", path)
+		}
+	}
+}
+
+func TestMetricsRateLimitRejections(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	global.Logger = logger.NewLogger()
+	l := &reloadingLimiter{bucket: stubBucket{available: 0, capacity: 1, rate: 1}}
+	r := gin.New()
+	r.Use(Metrics(), RateLimiter(l))
+	r.GET("/auth", func(c *gin.Context) {})
+
+	rejected := metrics.RateLimitRejectionsTotal.WithLabelValues("GET", "/auth")
+	requests := metrics.HTTPRequestsTotal.WithLabelValues("GET", "/auth", "429")
+	beforeRejected, beforeRequests := testutil.ToFloat64(rejected), testutil.ToFloat64(requests)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/auth", nil))
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("status = %d, want 429", w.Code)
+	}
+	if got := testutil.ToFloat64(rejected) - beforeRejected; got != 1 {
+		t.Errorf("rate_limit_rejections_total increased by %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(requests) - beforeRequests; got != 1 {
+		t.Errorf("http_requests_total{status=429} increased by %v, want 1", got)
+	}
+}
+
+func TestMetricsEndpoint(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Metrics())
+	r.GET("/api/v1/tags", func(c *gin.Context) {})
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/api/v1/tags", nil))
+
+	body := scrape(t)
+	for _, want := range []string{
+		`blog_service_http_requests_total{method="GET",route="/api/v1/tags",status="200"}`,
+		`blog_service_http_request_duration_seconds_bucket{method="GET",route="/api/v1/tags",status="200",le="+Inf"}`,
+		`blog_service_http_request_duration_seconds_count{method="GET",route="/api/v1/tags",status="200"}`,
+		"# TYPE blog_service_rate_limit_rejections_total counter",
+		"go_goroutines",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("/metrics does not contain %s", want)
+		}
+	}
+}
+
+func scrape(t *testing.T) string {
+	t.Helper()
+	r := gin.New()
+	r.GET("/metrics", gin.WrapH(metrics.Handler()))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET /metrics status = %d", w.Code)
+	}
+	body, err := io.ReadAll(w.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(body)
+}
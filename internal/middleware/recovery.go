@@ -12,27 +12,29 @@ import (
 )
 
 func Recovery() gin.HandlerFunc{
-	defailtMailer := email.NewEmail(&email.SMTPInfo{
-		Host: global.EmailSetting.Host,
-		Port: global.EmailSetting.Port,
-		IsSSL: global.EmailSetting.IsSSL,
-		UserName: global.EmailSetting.UserName,
-		Password: global.EmailSetting.Password,
-		From: global.EmailSetting.From,
-	})
 	return func(c *gin.Context) {
 		defer func() {
 			if err := recover();err != nil {
 				s := "panic recover err: %v"
-				global.Logger.WithCallersFrames().Errorf(s,err)
+				global.Logger.WithContext(c.Request.Context()).WithCallersFrames().Errorf(s,err)
 
+				//每次按当前的 Email 配置发送，热加载后立即生效
+				emailSetting := global.EmailSetting.Load()
+				defailtMailer := email.NewEmail(&email.SMTPInfo{
+					Host: emailSetting.Host,
+					Port: emailSetting.Port,
+					IsSSL: emailSetting.IsSSL,
+					UserName: emailSetting.UserName,
+					Password: emailSetting.Password,
+					From: emailSetting.From,
+				})
 				err := defailtMailer.SendMail(
-					global.EmailSetting.To,
+					emailSetting.To,
 					fmt.Sprintf("抛出异常，发生时间：%d",time.Now().Unix()),
 					fmt.Sprintf("错误信息：%v",err),
 				)
 				if err != nil {
-					global.Logger.Panicf("mail.SendMail err: %v",err)
+					global.Logger.WithContext(c.Request.Context()).WithError(err).Errorf("mail.SendMail err: %v",err)
 				}
 				app.NewResponse(c).ToErrorResponse(errcode.ServerError)
 				c.Abort()
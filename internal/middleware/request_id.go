@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ludyyy-lu/goBlogService/pkg/logger"
+)
+
+const RequestIDHeader = "X-Request-ID"
+
+// 为每个请求生成或沿用上游传入的请求 ID，并放入请求的 context 中供日志使用
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !validRequestID(requestID) {
+			requestID = newRequestID()
+		}
+		c.Set("request_id", requestID)
+		c.Header(RequestIDHeader, requestID)
+
+		path := c.FullPath()
+		if path == "" {
+			path = c.Request.URL.Path
+		}
+		info := &logger.RequestInfo{
+			RequestID: requestID,
+			Method:    c.Request.Method,
+			Path:      path,
+		}
+		c.Request = c.Request.WithContext(logger.NewContext(c.Request.Context(), info))
+		c.Next()
+	}
+}
+
+// 只接受长度合理的可见 ASCII 字符，防止日志注入
+func validRequestID(id string) bool {
+	if id == "" || len(id) > 128 {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
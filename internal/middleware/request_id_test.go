@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ludyyy-lu/goBlogService/pkg/logger"
+)
+
+func TestRequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	generated := regexp.MustCompile(`^[0-9a-f]{32}$`)
+	tests := []struct {
+		name   string
+		header string
+		reuse  bool
+	}{
+		{"generated when missing", "", false},
+		{"reuses upstream id", "req-123", true},
+		{"rejects control characters", "req\n123", false},
+		{"rejects spaces", "req 123", false},
+		{"rejects overlong id", strings.Repeat("a", 129), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var info *logger.RequestInfo
+			var ctxID string
+			r := gin.New()
+			r.Use(RequestID())
+			r.GET("/api/v1/tags/:id", func(c *gin.Context) {
+				info, _ = logger.FromContext(c.Request.Context())
+				ctxID = c.GetString("request_id")
+			})
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/tags/7?token=secret", nil)
+			if tt.header != "" {
+				req.Header.Set(RequestIDHeader, tt.header)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			id := w.Header().Get(RequestIDHeader)
+			if tt.reuse && id != tt.header {
+				t.Errorf("%s = %q, want %q", RequestIDHeader, id, tt.header)
+			}
+			if !tt.reuse && !generated.MatchString(id) {
+				t.Errorf("%s = %q, want a generated id", RequestIDHeader, id)
+			}
+			if ctxID != id {
+				t.Errorf("request_id = %q, want %q", ctxID, id)
+			}
+			want := logger.RequestInfo{RequestID: id, Method: http.MethodGet, Path: "/api/v1/tags/:id"}
+			if info == nil || *info != want {
+				t.Errorf("RequestInfo = %+v, want %+v", info, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func newSpanRecorder(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	prevProvider, prevPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prevProvider)
+		otel.SetTextMapPropagator(prevPropagator)
+	})
+	return recorder
+}
+
+func TestTracing(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	const (
+		traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+		spanID  = "00f067aa0ba902b7"
+	)
+	tests := []struct {
+		name        string
+		path        string
+		traceparent string
+		status      int
+		wantName    string
+		wantParent  bool
+	}{
+		{"continues upstream trace", "/api/v1/tags/7", "00-" + traceID + "-" + spanID + "-01", http.StatusOK, "GET /api/v1/tags/:id", true},
+		{"starts new trace", "/api/v1/tags/7", "", http.StatusOK, "GET /api/v1/tags/:id", false},
+		{"ignores malformed traceparent", "/api/v1/tags/7", "00-zz-" + spanID + "-01", http.StatusOK, "GET /api/v1/tags/:id", false},
+		{"marks server errors", "/api/v1/tags/7", "", http.StatusInternalServerError, "GET /api/v1/tags/:id", false},
+		{"unmatched route", "/missing", "", http.StatusNotFound, "GET " + unmatchedRoute, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := newSpanRecorder(t)
+			var handlerSpan trace.SpanContext
+			r := gin.New()
+			r.Use(Tracing())
+			r.GET("/api/v1/tags/:id", func(c *gin.Context) {
+				handlerSpan = trace.SpanContextFromContext(c.Request.Context())
+				c.Status(tt.status)
+			})
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.traceparent != "" {
+				req.Header.Set("traceparent", tt.traceparent)
+			}
+			r.ServeHTTP(httptest.NewRecorder(), req)
+
+			spans := recorder.Ended()
+			if len(spans) != 1 {
+				t.Fatalf("ended %d spans, want 1", len(spans))
+			}
+			span := spans[0]
+			if span.Name() != tt.wantName {
+				t.Errorf("name = %q, want %q", span.Name(), tt.wantName)
+			}
+			if span.SpanKind() != trace.SpanKindServer {
+				t.Errorf("kind = %v, want server", span.SpanKind())
+			}
+			if tt.wantParent {
+				if got := span.SpanContext().TraceID().String(); got != traceID {
+					t.Errorf("trace id = %s, want %s", got, traceID)
+				}
+				if got := span.Parent().SpanID().String(); got != spanID || !span.Parent().IsRemote() {
+					t.Errorf("parent = %s (remote %v), want remote %s", got, span.Parent().IsRemote(), spanID)
+				}
+			} else if span.Parent().IsValid() {
+				t.Errorf("parent = %v, want a root span", span.Parent())
+			}
+			if tt.path != "/missing" && handlerSpan.SpanID() != span.SpanContext().SpanID() {
+				t.Error("request context does not carry the server span")
+			}
+			if !hasAttribute(span, semconv.HTTPResponseStatusCode(tt.status)) {
+				t.Errorf("attributes = %v, want status %d", span.Attributes(), tt.status)
+			}
+			wantCode := codes.Unset
+			if tt.status >= 500 {
+				wantCode = codes.Error
+			}
+			if span.Status().Code != wantCode {
+				t.Errorf("status = %v, want %v", span.Status().Code, wantCode)
+			}
+		})
+	}
+}
+
+func hasAttribute(span sdktrace.ReadOnlySpan, want attribute.KeyValue) bool {
+	for _, kv := range span.Attributes() {
+		if kv == want {
+			return true
+		}
+	}
+	return false
+}
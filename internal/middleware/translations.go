@@ -10,26 +10,57 @@ import (
 	"github.com/go-playground/validator/v10"
 	en_translations "github.com/go-playground/validator/v10/translations/en"
 	zh_translations "github.com/go-playground/validator/v10/translations/zh"
+	zh_tw_translations "github.com/go-playground/validator/v10/translations/zh_tw"
+	"github.com/ludyyy-lu/goBlogService/global"
+	"github.com/ludyyy-lu/goBlogService/pkg/i18n"
+	"github.com/ludyyy-lu/goBlogService/pkg/validation"
 )
 
+// 协商出的语言与 universal-translator 中语言名称的对应关系
+var translatorLocales = map[string]string{
+	i18n.ZH:   "zh",
+	i18n.EN:   "en",
+	i18n.ZHTW: "zh_Hant_TW",
+}
+
+var defaultTranslations = map[string]func(*validator.Validate, ut.Translator) error{
+	i18n.ZH:   zh_translations.RegisterDefaultTranslations,
+	i18n.EN:   en_translations.RegisterDefaultTranslations,
+	i18n.ZHTW: zh_tw_translations.RegisterDefaultTranslations,
+}
+
+// 翻译器在创建中间件时构建一次，请求中只做语言协商，避免并发修改共享的校验器
 func Translations() gin.HandlerFunc {
+	translators := newTranslators()
 	return func(c *gin.Context) {
-		uni := ut.New(en.New(), zh.New(), zh_Hant_TW.New())
-		locale := c.GetHeader("locale")
-		trans, _ := uni.GetTranslator(locale)
-		v, ok := binding.Validator.Engine().(*validator.Validate)
-		if ok {
-			switch locale {
-			case "zh":
-				_ = zh_translations.RegisterDefaultTranslations(v, trans)
-				//break
-			case "en":
-				_ = en_translations.RegisterDefaultTranslations(v, trans)
-				//break
-			default:
-				_ = zh_translations.RegisterDefaultTranslations(v, trans)
-				//break
-			}
+		locale := i18n.FromRequest(c.Request)
+		//错误码信息与校验信息使用同一个协商结果
+		c.Set("locale", locale)
+		if trans, ok := translators[locale]; ok {
+			c.Set("trans", trans)
+		}
+		c.Next()
+	}
+}
+
+func newTranslators() map[string]ut.Translator {
+	translators := make(map[string]ut.Translator, len(translatorLocales))
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return translators
+	}
+	uni := ut.New(en.New(), zh.New(), zh_Hant_TW.New())
+	for locale, name := range translatorLocales {
+		trans, _ := uni.GetTranslator(name)
+		if err := defaultTranslations[locale](v, trans); err != nil {
+			global.Logger.Errorf("register %s validator translations err: %v", locale, err)
+			continue
+		}
+		if err := validation.RegisterTranslations(v, trans, locale); err != nil {
+			global.Logger.Errorf("register %s custom validator translations err: %v", locale, err)
+			continue
 		}
+		translators[locale] = trans
 	}
+	return translators
 }
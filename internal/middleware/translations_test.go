@@ -0,0 +1,138 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	"github.com/ludyyy-lu/goBlogService/global"
+	"github.com/ludyyy-lu/goBlogService/pkg/i18n"
+	"github.com/ludyyy-lu/goBlogService/pkg/logger"
+)
+
+// 统计 Engine 的调用次数，翻译器只在构建时向校验器注册
+type countingValidator struct {
+	v       *validator.Validate
+	engines atomic.Int32
+}
+
+func (cv *countingValidator) ValidateStruct(obj any) error { return cv.v.Struct(obj) }
+
+func (cv *countingValidator) Engine() any {
+	cv.engines.Add(1)
+	return cv.v
+}
+
+func setCountingValidator(t *testing.T) *countingValidator {
+	t.Helper()
+	prev := binding.Validator
+	v := validator.New()
+	v.SetTagName("binding")
+	cv := &countingValidator{v: v}
+	binding.Validator = cv
+	t.Cleanup(func() { binding.Validator = prev })
+	return cv
+}
+
+type nameRequest struct {
+	Name string `form:"name" binding:"required"`
+}
+
+func TestTranslations(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	global.Logger = logger.NewLogger(logger.NewSink(&bytes.Buffer{}, logger.FormatJSON))
+	setCountingValidator(t)
+
+	r := gin.New()
+	r.Use(Translations())
+	var locale, msg string
+	r.GET("/api/v1/tags", func(c *gin.Context) {
+		locale = c.GetString("locale")
+		msg = ""
+		var errs validator.ValidationErrors
+		if err := c.ShouldBind(&nameRequest{}); errors.As(err, &errs) {
+			if trans, ok := c.Get("trans"); ok {
+				msg = errs[0].Translate(trans.(ut.Translator))
+			}
+		}
+	})
+
+	tests := []struct {
+		name           string
+		target         string
+		header         string
+		acceptLanguage string
+		wantLocale     string
+		wantMsg        string
+	}{
+		{"default", "/api/v1/tags", "", "", i18n.ZH, "Name为必填字段"},
+		{"accept language", "/api/v1/tags", "", "fr, en;q=0.8, zh;q=0.5", i18n.EN, "Name is a required field"},
+		{"locale header wins", "/api/v1/tags", "zh-TW", "en", i18n.ZHTW, "Name為必填欄位"},
+		{"locale query", "/api/v1/tags?locale=en", "", "zh-TW", i18n.EN, "Name is a required field"},
+		{"locale header before query", "/api/v1/tags?locale=en", "zh-Hant", "", i18n.ZHTW, "Name為必填欄位"},
+		{"unsupported header falls back to query", "/api/v1/tags?locale=zh_tw", "fr", "en", i18n.ZHTW, "Name為必填欄位"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			if tt.header != "" {
+				req.Header.Set("locale", tt.header)
+			}
+			if tt.acceptLanguage != "" {
+				req.Header.Set("Accept-Language", tt.acceptLanguage)
+			}
+			r.ServeHTTP(httptest.NewRecorder(), req)
+
+			if locale != tt.wantLocale {
+				t.Errorf("locale = %q, want %q", locale, tt.wantLocale)
+			}
+			if msg != tt.wantMsg {
+				t.Errorf("message = %q, want %q", msg, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestTranslationsBuiltOnce(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	global.Logger = logger.NewLogger(logger.NewSink(&bytes.Buffer{}, logger.FormatJSON))
+	cv := setCountingValidator(t)
+
+	r := gin.New()
+	r.Use(Translations())
+	var mu sync.Mutex
+	seen := make(map[ut.Translator]bool)
+	r.GET("/", func(c *gin.Context) {
+		trans, _ := c.Get("trans")
+		mu.Lock()
+		seen[trans.(ut.Translator)] = true
+		mu.Unlock()
+	})
+	//并发请求共用创建中间件时构建的翻译器，不再修改共享的校验器
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Accept-Language", "en")
+			r.ServeHTTP(httptest.NewRecorder(), req)
+		}()
+	}
+	wg.Wait()
+
+	if n := cv.engines.Load(); n != 1 {
+		t.Errorf("validator engine fetched %d times, want once when the middleware is created", n)
+	}
+	if len(seen) != 1 {
+		t.Errorf("requests got %d translators, want the same one", len(seen))
+	}
+}
@@ -5,6 +5,7 @@ import "github.com/ludyyy-lu/goBlogService/pkg/app"
 type Article struct {
 	*Model
 	Title         string `json:"title"`
+	Slug          string `json:"slug"`
 	Desc          string `json:"desc"`
 	Content       string `json:"content"`
 	CoverImageUrl string `json:"cover_image_url"`
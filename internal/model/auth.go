@@ -26,3 +26,16 @@ func (a Auth) Get(db *gorm.DB) (Auth, error) {
 	}
 	return auth, nil
 }
+
+func (a Auth) GetByID(db *gorm.DB) (Auth, error) {
+	var auth Auth
+	err := db.Where("id = ? AND is_del = ?", a.ID, 0).First(&auth).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return auth, err
+	}
+	return auth, nil
+}
+
+func (a *Auth) Create(db *gorm.DB) error {
+	return db.Create(a).Error
+}
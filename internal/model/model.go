@@ -9,6 +9,7 @@ import (
 	_ "github.com/jinzhu/gorm/dialects/mysql"
 	"github.com/ludyyy-lu/goBlogService/global"
 	"github.com/ludyyy-lu/goBlogService/pkg/setting"
+	"github.com/ludyyy-lu/goBlogService/pkg/tracer"
 )
 
 type Model struct {
@@ -46,16 +47,22 @@ func NewDBEngine(databaseSetting *setting.DatabaseSettingS) (*gorm.DB, error) {
 	if global.ServerSetting.RunMode == "debug" {
 		db.LogMode(true)
 	}
+	Setup(db)
+
+	db.DB().SetMaxIdleConns(databaseSetting.MaxIdleConns)
+	db.DB().SetMaxOpenConns(databaseSetting.MaxOpenConns)
+	return db, nil
+}
+
+// 使用单数表名并注册时间戳、软删除和链路追踪回调
+func Setup(db *gorm.DB) {
 	db.SingularTable(true)
 
 	//注册回调行为
 	db.Callback().Create().Replace("gorm:update_time_stamp", updateTimeStampForCreateCallback)
 	db.Callback().Update().Replace("gorm:update_time_stamp", updateTimeStampForUpdateCallback)
 	db.Callback().Delete().Replace("gorm:delete", deleteCallback)
-
-	db.DB().SetMaxIdleConns(databaseSetting.MaxIdleConns)
-	db.DB().SetMaxOpenConns(databaseSetting.MaxOpenConns)
-	return db, nil
+	tracer.RegisterCallbacks(db)
 }
 
 func updateTimeStampForCreateCallback(scope *gorm.Scope) {
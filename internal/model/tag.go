@@ -33,6 +33,13 @@ func (t Tag) Count(db *gorm.DB) (int, error) {
 	return count, nil
 }
 
+// 统计给定 ID 中未删除的标签数量
+func (t Tag) CountByIDs(db *gorm.DB, ids []uint32) (int, error) {
+	var count int
+	err := db.Model(&t).Where("id IN (?) AND is_del = ?", ids, 0).Count(&count).Error
+	return count, err
+}
+
 func (t Tag) List(db *gorm.DB, pageOffset, pageSize int) ([]*Tag, error) {
 	var tags []*Tag
 	var err error
@@ -49,6 +56,19 @@ func (t Tag) List(db *gorm.DB, pageOffset, pageSize int) ([]*Tag, error) {
 	return tags, nil
 }
 
+// 按 ID 或名称获取未删除的标签，不存在时返回 gorm.ErrRecordNotFound
+func (t Tag) Get(db *gorm.DB) (Tag, error) {
+	var tag Tag
+	if t.Model != nil && t.ID > 0 {
+		db = db.Where("id = ?", t.ID)
+	}
+	if t.Name != "" {
+		db = db.Where("name = ?", t.Name)
+	}
+	err := db.Where("is_del = ?", 0).First(&tag).Error
+	return tag, err
+}
+
 func (t Tag) Create(db *gorm.DB) error {
 	return db.Create(&t).Error
 }
@@ -61,6 +81,14 @@ func (t Tag) Update(db *gorm.DB, val any) error {
 	return nil
 }
 
+// 没有删除任何记录时返回 gorm.ErrRecordNotFound
 func (t Tag) Delete(db *gorm.DB) error {
-	return db.Where("id = ? AND is_del = ?", t.Model.ID, 0).Delete(&t).Error
+	db = db.Where("id = ? AND is_del = ?", t.Model.ID, 0).Delete(&t)
+	if db.Error != nil {
+		return db.Error
+	}
+	if db.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
 }
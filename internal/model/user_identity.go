@@ -0,0 +1,40 @@
+package model
+
+import "github.com/jinzhu/gorm"
+
+// 外部身份（OIDC 的 iss + sub）与本地认证信息的映射
+type UserIdentity struct {
+	*Model
+	AuthID  uint32 `json:"auth_id"`
+	Issuer  string `json:"issuer"`
+	Subject string `json:"subject"`
+	Email   string `json:"email"`
+	Name    string `json:"name"`
+}
+
+func (u UserIdentity) TableName() string {
+	return "blog_user_identity"
+}
+
+func (u UserIdentity) Get(db *gorm.DB) (UserIdentity, error) {
+	var identity UserIdentity
+	db = db.Where(
+		"issuer = ? AND subject = ? AND is_del = ?",
+		u.Issuer,
+		u.Subject,
+		0,
+	)
+	err := db.First(&identity).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return identity, err
+	}
+	return identity, nil
+}
+
+func (u UserIdentity) Create(db *gorm.DB) error {
+	return db.Create(&u).Error
+}
+
+func (u UserIdentity) Update(db *gorm.DB, values any) error {
+	return db.Model(&UserIdentity{}).Where("id = ? AND is_del = ?", u.ID, 0).Updates(values).Error
+}
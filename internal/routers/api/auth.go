@@ -1,6 +1,8 @@
 package api
 
 import (
+	"errors"
+
 	"github.com/gin-gonic/gin"
 	"github.com/ludyyy-lu/goBlogService/global"
 	"github.com/ludyyy-lu/goBlogService/internal/service"
@@ -13,23 +15,26 @@ func GetAuth(c *gin.Context) {
 	response := app.NewResponse(c)
 	valid, errs := app.BindAndValid(c, &param)
 	if !valid {
-		global.Logger.Errorf("app.BindAndValid errs: %v", errs)
-		errRsp := errcode.InvalidParams.WithDetails(errs.Errors()...)
-		response.ToErrorResponse(errRsp)
+		global.Logger.WithContext(c.Request.Context()).Errorf("app.BindAndValid errs: %v", errs)
+		response.ToValidErrorResponse(errs)
 		return
 	}
 
 	svc := service.New(c.Request.Context())
 	err := svc.CheckAuth(&param)
 	if err != nil {
-		global.Logger.Errorf("svc.CheckAuth err: %v", err)
-		response.ToErrorResponse(errcode.UnauthorizedAuthNotExist)
+		global.Logger.WithContext(c.Request.Context()).WithError(err).Errorf("svc.CheckAuth err: %v", err)
+		if errors.Is(err, service.ErrNotFound) {
+			response.ToErrorResponse(errcode.UnauthorizedAuthNotExist.WithCause(err))
+			return
+		}
+		response.ToErrorResponse(errcode.ServerError.WithCause(err))
 		return
 	}
 
 	token, err := app.GenerateToken(param.AppKey, param.AppSecret)
 	if err != nil {
-		global.Logger.Errorf("app.GenerateToken err: %v", err)
+		global.Logger.WithContext(c.Request.Context()).Errorf("app.GenerateToken err: %v", err)
 		response.ToErrorResponse(errcode.UnauthorizedTokenGenerate)
 		return
 	}
@@ -0,0 +1,32 @@
+package api
+
+import (
+	"bytes"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ludyyy-lu/goBlogService/global"
+	"github.com/ludyyy-lu/goBlogService/pkg/app"
+	"github.com/ludyyy-lu/goBlogService/pkg/errcode"
+)
+
+type ErrCode struct{}
+
+func NewErrCode() ErrCode {
+	return ErrCode{}
+}
+
+// 列出所有错误码，format=markdown 时返回 Markdown 表格
+func (e ErrCode) List(c *gin.Context) {
+	if c.Query("format") == "markdown" {
+		var buf bytes.Buffer
+		if err := errcode.WriteMarkdown(&buf); err != nil {
+			global.Logger.WithContext(c.Request.Context()).Errorf("errcode.WriteMarkdown err: %v", err)
+			app.NewResponse(c).ToErrorResponse(errcode.ServerError)
+			return
+		}
+		c.Data(http.StatusOK, "text/markdown; charset=utf-8", buf.Bytes())
+		return
+	}
+	app.NewResponse(c).ToResponse(gin.H{"list": errcode.Catalogue()})
+}
@@ -0,0 +1,31 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ludyyy-lu/goBlogService/pkg/health"
+)
+
+type Health struct {
+	checker *health.Checker
+}
+
+func NewHealth(checker *health.Checker) Health {
+	return Health{checker: checker}
+}
+
+// 存活探针，只要进程能处理请求即返回成功
+func (h Health) Liveness(c *gin.Context) {
+	c.JSON(http.StatusOK, health.Report{Status: health.StatusOK})
+}
+
+// 就绪探针，依赖检查失败或服务正在关闭时返回 503
+func (h Health) Readiness(c *gin.Context) {
+	report := h.checker.Check(c.Request.Context())
+	status := http.StatusOK
+	if report.Status != health.StatusOK {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, report)
+}
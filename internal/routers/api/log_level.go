@@ -0,0 +1,70 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ludyyy-lu/goBlogService/global"
+	"github.com/ludyyy-lu/goBlogService/pkg/app"
+	"github.com/ludyyy-lu/goBlogService/pkg/errcode"
+	"github.com/ludyyy-lu/goBlogService/pkg/logger"
+)
+
+type LogLevel struct{}
+
+func NewLogLevel() LogLevel {
+	return LogLevel{}
+}
+
+type UpdateLogLevelRequest struct {
+	// 为空时修改全局级别
+	Component string `form:"component" binding:"max=100"`
+	// 为空且指定了 component 时删除该组件的级别覆盖
+	Level string `form:"level" binding:"omitempty,oneof=debug info warn error fatal panic"`
+}
+
+func (l LogLevel) Get(c *gin.Context) {
+	app.NewResponse(c).ToResponse(logLevels())
+}
+
+// 运行时修改日志级别，无需重启服务
+func (l LogLevel) Update(c *gin.Context) {
+	param := UpdateLogLevelRequest{}
+	response := app.NewResponse(c)
+	valid, errs := app.BindAndValid(c, &param)
+	if !valid {
+		global.Logger.WithContext(c.Request.Context()).Errorf("app.BindAndValid errs: %v", errs)
+		response.ToValidErrorResponse(errs)
+		return
+	}
+	if param.Level == "" {
+		if param.Component == "" {
+			response.ToErrorResponse(errcode.InvalidParams.WithDetails("level is required"))
+			return
+		}
+		global.Logger.ResetComponentLevel(param.Component)
+	} else {
+		level, err := logger.ParseLevel(param.Level)
+		if err != nil {
+			response.ToErrorResponse(errcode.InvalidParams.WithDetails(err.Error()))
+			return
+		}
+		if param.Component == "" {
+			global.Logger.SetLevel(level)
+		} else {
+			global.Logger.SetComponentLevel(param.Component, level)
+		}
+	}
+	global.Logger.WithContext(c.Request.Context()).Warnf("log level changed: component=%q level=%q", param.Component, param.Level)
+	response.ToResponse(logLevels())
+}
+
+func logLevels() gin.H {
+	min, components := global.Logger.Levels()
+	levels := make(map[string]string, len(components))
+	for k, v := range components {
+		levels[k] = v.String()
+	}
+	return gin.H{
+		"level":  min.String(),
+		"levels": levels,
+	}
+}
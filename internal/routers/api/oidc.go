@@ -0,0 +1,114 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ludyyy-lu/goBlogService/global"
+	"github.com/ludyyy-lu/goBlogService/internal/service"
+	"github.com/ludyyy-lu/goBlogService/pkg/app"
+	"github.com/ludyyy-lu/goBlogService/pkg/errcode"
+	"github.com/ludyyy-lu/goBlogService/pkg/oidc"
+)
+
+type OIDC struct {
+	provider *oidc.Provider
+	states   *oidc.StateStore
+}
+
+func NewOIDC() OIDC {
+	return OIDC{
+		provider: oidc.NewProvider(&oidc.Config{
+			Issuer:       global.OIDCSetting.Issuer,
+			ClientID:     global.OIDCSetting.ClientID,
+			ClientSecret: global.OIDCSetting.ClientSecret,
+			RedirectURL:  global.OIDCSetting.RedirectURL,
+			Scopes:       global.OIDCSetting.Scopes,
+		}),
+		states: oidc.NewStateStore(global.OIDCSetting.StateExpire, global.OIDCSetting.MaxStates),
+	}
+}
+
+// 生成 state、nonce 和 PKCE 参数后跳转到 IdP 的登录页
+func (o OIDC) Login(c *gin.Context) {
+	response := app.NewResponse(c)
+	state, err := oidc.RandomString(16)
+	if err != nil {
+		global.Logger.WithContext(c.Request.Context()).Errorf("oidc.RandomString err: %v", err)
+		response.ToErrorResponse(errcode.ServerError)
+		return
+	}
+	nonce, err := oidc.RandomString(16)
+	if err != nil {
+		global.Logger.WithContext(c.Request.Context()).Errorf("oidc.RandomString err: %v", err)
+		response.ToErrorResponse(errcode.ServerError)
+		return
+	}
+	verifier, err := oidc.NewCodeVerifier()
+	if err != nil {
+		global.Logger.WithContext(c.Request.Context()).Errorf("oidc.NewCodeVerifier err: %v", err)
+		response.ToErrorResponse(errcode.ServerError)
+		return
+	}
+	authURL, err := o.provider.AuthCodeURL(c.Request.Context(), state, nonce, oidc.CodeChallengeS256(verifier))
+	if err != nil {
+		global.Logger.WithContext(c.Request.Context()).Errorf("provider.AuthCodeURL err: %v", err)
+		response.ToErrorResponse(errcode.UnauthorizedOIDCFail)
+		return
+	}
+	o.states.Save(state, &oidc.AuthSession{Nonce: nonce, CodeVerifier: verifier})
+	c.Redirect(http.StatusFound, authURL)
+}
+
+// IdP 回调：校验 state，换取并校验 ID Token，签发本服务的 JWT
+func (o OIDC) Callback(c *gin.Context) {
+	response := app.NewResponse(c)
+	if e := c.Query("error"); e != "" {
+		global.Logger.WithContext(c.Request.Context()).Errorf("oidc callback err: %s %s", e, c.Query("error_description"))
+		response.ToErrorResponse(errcode.UnauthorizedOIDCFail.WithDetails(e))
+		return
+	}
+	param := service.OIDCCallbackRequest{}
+	valid, errs := app.BindAndValid(c, &param)
+	if !valid {
+		global.Logger.WithContext(c.Request.Context()).Errorf("app.BindAndValid errs: %v", errs)
+		response.ToValidErrorResponse(errs)
+		return
+	}
+	session, ok := o.states.Take(param.State)
+	if !ok {
+		response.ToErrorResponse(errcode.UnauthorizedOIDCFail.WithDetails("invalid or expired state"))
+		return
+	}
+
+	ctx := c.Request.Context()
+	token, err := o.provider.Exchange(ctx, param.Code, session.CodeVerifier)
+	if err != nil {
+		global.Logger.WithContext(c.Request.Context()).Errorf("provider.Exchange err: %v", err)
+		response.ToErrorResponse(errcode.UnauthorizedOIDCFail)
+		return
+	}
+	claims, err := o.provider.VerifyIDToken(ctx, token.IDToken, session.Nonce)
+	if err != nil {
+		global.Logger.WithContext(c.Request.Context()).Errorf("provider.VerifyIDToken err: %v", err)
+		response.ToErrorResponse(errcode.UnauthorizedOIDCFail)
+		return
+	}
+
+	svc := service.New(ctx)
+	auth, err := svc.OIDCSignIn(claims)
+	if err != nil {
+		global.Logger.WithContext(c.Request.Context()).Errorf("svc.OIDCSignIn err: %v", err)
+		response.ToErrorResponse(errcode.UnauthorizedOIDCFail)
+		return
+	}
+	jwtToken, err := app.GenerateToken(auth.AppKey, auth.AppSecret)
+	if err != nil {
+		global.Logger.WithContext(c.Request.Context()).Errorf("app.GenerateToken err: %v", err)
+		response.ToErrorResponse(errcode.UnauthorizedTokenGenerate)
+		return
+	}
+	response.ToResponse(gin.H{
+		"token": jwtToken,
+	})
+}
@@ -1,6 +1,8 @@
 package api
 
 import (
+	"errors"
+
 	"github.com/gin-gonic/gin"
 	"github.com/ludyyy-lu/goBlogService/global"
 	"github.com/ludyyy-lu/goBlogService/internal/service"
@@ -32,9 +34,13 @@ func (u Upload) UploadFile(c *gin.Context) {
 	svc := service.New(c.Request.Context())
 	fileInfo, err := svc.UploadFile(upload.FileType(fileType), file, fileHeader)
 	if err != nil {
-		global.Logger.Errorf("svc.UploadFile err: %v", err)
-		errRsp := errcode.ErrorUploadFileFail.WithDetails(err.Error())
-		response.ToErrorResponse(errRsp)
+		global.Logger.WithContext(c.Request.Context()).WithError(err).Errorf("svc.UploadFile err: %v", err)
+		//只有文件本身不符合要求时才把原因返回给客户端
+		if errors.Is(err, service.ErrInvalidFile) {
+			response.ToErrorResponse(errcode.ErrorUploadFileType.WithDetails(err.Error()).WithCause(err))
+			return
+		}
+		response.ToErrorResponse(errcode.ErrorUploadFileFail.WithCause(err))
 		return
 	}
 	response.ToResponse(gin.H{
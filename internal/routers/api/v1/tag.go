@@ -1,6 +1,8 @@
 package v1
 
 import (
+	"errors"
+
 	"github.com/gin-gonic/gin"
 	"github.com/ludyyy-lu/goBlogService/global"
 	"github.com/ludyyy-lu/goBlogService/internal/service"
@@ -32,23 +34,22 @@ func (t Tag) List(c *gin.Context) {
 	response := app.NewResponse(c)
 	valid, errs := app.BindAndValid(c, &param)
 	if !valid {
-		global.Logger.Errorf("app.BindAndValid errs: %v", errs)
-		errRsp := errcode.InvalidParams.WithDetails(errs.Errors()...)
-		response.ToErrorResponse(errRsp)
+		global.Logger.WithContext(c.Request.Context()).Errorf("app.BindAndValid errs: %v", errs)
+		response.ToValidErrorResponse(errs)
 		return
 	}
 	svc := service.New(c.Request.Context())
 	pager := app.Pager{Page: app.GetPage(c), PageSize: app.GetPageSize(c)}
 	totalRows, err := svc.CountTag(&service.CountTagRequest{Name: param.Name, State: param.State})
 	if err != nil {
-		global.Logger.Errorf("svc.CountTag err err: %v", err)
-		response.ToErrorResponse(errcode.ErrorCountTagFail)
+		global.Logger.WithContext(c.Request.Context()).WithError(err).Errorf("svc.CountTag err: %v", err)
+		response.ToErrorResponse(tagError(err, errcode.ErrorCountTagFail))
 		return
 	}
 	tags, err := svc.GetTagList(&param, &pager)
 	if err != nil {
-		global.Logger.Errorf("svc.GetTagList err err: %v", err)
-		response.ToErrorResponse(errcode.ErrorGetTagListFail)
+		global.Logger.WithContext(c.Request.Context()).WithError(err).Errorf("svc.GetTagList err: %v", err)
+		response.ToErrorResponse(tagError(err, errcode.ErrorGetTagListFail))
 		return
 	}
 	response.ToResponseList(tags, totalRows)
@@ -59,16 +60,15 @@ func (t Tag) Create(c *gin.Context) {
 	response := app.NewResponse(c)
 	valid, errs := app.BindAndValid(c, &param)
 	if !valid {
-		global.Logger.Errorf("app.BindAndValid errs: %v", errs)
-		errRsp := errcode.InvalidParams.WithDetails(errs.Errors()...)
-		response.ToErrorResponse(errRsp)
+		global.Logger.WithContext(c.Request.Context()).Errorf("app.BindAndValid errs: %v", errs)
+		response.ToValidErrorResponse(errs)
 		return
 	}
 	svc := service.New(c.Request.Context())
 	err := svc.CreateTag(&param)
 	if err != nil {
-		global.Logger.Errorf("svc.CreateTag err err: %v", err)
-		response.ToErrorResponse(errcode.ErrorCreateTagFail)
+		global.Logger.WithContext(c.Request.Context()).WithError(err).Errorf("svc.CreateTag err: %v", err)
+		response.ToErrorResponse(tagError(err, errcode.ErrorCreateTagFail))
 		return
 	}
 	response.ToResponse(gin.H{})
@@ -81,17 +81,16 @@ func (t Tag) Update(c *gin.Context) {
 	response := app.NewResponse(c)
 	valid, errs := app.BindAndValid(c, &param)
 	if !valid {
-		global.Logger.Errorf("app.BindAndValid errs: %v", errs)
-		errRsp := errcode.InvalidParams.WithDetails(errs.Errors()...)
-		response.ToErrorResponse(errRsp)
+		global.Logger.WithContext(c.Request.Context()).Errorf("app.BindAndValid errs: %v", errs)
+		response.ToValidErrorResponse(errs)
 		return
 	}
 
 	svc := service.New(c.Request.Context())
 	err := svc.UpdateTag(&param)
 	if err != nil {
-		global.Logger.Errorf("svc.UpdateTag err err: %v", err)
-		response.ToErrorResponse(errcode.ErrorUpdateTagFail)
+		global.Logger.WithContext(c.Request.Context()).WithError(err).Errorf("svc.UpdateTag err: %v", err)
+		response.ToErrorResponse(tagError(err, errcode.ErrorUpdateTagFail))
 		return
 	}
 	response.ToResponse(gin.H{})
@@ -104,17 +103,29 @@ func (t Tag) Delete(c *gin.Context) {
 	response := app.NewResponse(c)
 	valid, errs := app.BindAndValid(c, &param)
 	if !valid {
-		global.Logger.Errorf("app.BindAndValid errs: %v", errs)
-		errRsp := errcode.InvalidParams.WithDetails(errs.Errors()...)
-		response.ToErrorResponse(errRsp)
+		global.Logger.WithContext(c.Request.Context()).Errorf("app.BindAndValid errs: %v", errs)
+		response.ToValidErrorResponse(errs)
 		return
 	}
 	svc := service.New(c.Request.Context())
 	err := svc.DeleteTag(&param)
 	if err != nil {
-		global.Logger.Errorf("svc.DleteTag err err: %v", err)
-		response.ToErrorResponse(errcode.ErrorDeleteTagFail)
+		global.Logger.WithContext(c.Request.Context()).WithError(err).Errorf("svc.DeleteTag err: %v", err)
+		response.ToErrorResponse(tagError(err, errcode.ErrorDeleteTagFail))
 		return
 	}
 	response.ToResponse(gin.H{})
 }
+
+// 将 service 返回的错误映射为对应的错误码，无法识别的错误使用 fallback
+func tagError(err error, fallback *errcode.Error) *errcode.Error {
+	switch {
+	case errors.Is(err, service.ErrNotFound):
+		return errcode.ErrorTagNotExist.WithCause(err)
+	case errors.Is(err, service.ErrDuplicate):
+		return errcode.ErrorTagExists.WithCause(err)
+	case errors.Is(err, service.ErrConflict):
+		return errcode.Conflict.WithCause(err)
+	}
+	return fallback.WithCause(err)
+}
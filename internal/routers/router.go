@@ -11,21 +11,102 @@ import (
 	"github.com/ludyyy-lu/goBlogService/internal/routers/api"
 	v1 "github.com/ludyyy-lu/goBlogService/internal/routers/api/v1"
 	"github.com/ludyyy-lu/goBlogService/pkg/limiter"
+	"github.com/ludyyy-lu/goBlogService/pkg/metrics"
+	"github.com/redis/go-redis/v9"
 	ginSwagger "github.com/swaggo/gin-swagger"
 	"github.com/swaggo/gin-swagger/swaggerFiles"
 )
 
-var methodLimiters = limiter.NewMethodLimiter().AddBuckets(
-	limiter.LimiterBucketRule{
-		Key:          "/auth",
-		FillInterval: time.Second,
-		Capacity:     10,
-		Quantum:      10,
-	},
+var (
+	rateLimiter *limiter.DynamicLimiter
+	//限流存储的连接只创建一次，重新加载限流规则时复用
+	rateLimitRedis *redis.Client
 )
 
-func NewRouter() *gin.Engine {
+// 按当前的 RateLimit 配置重新构建限流器，配置热加载时调用；配置有误时保留原来的限流器
+func ReloadLimiter() error {
+	if rateLimiter == nil {
+		return nil
+	}
+	l, err := newLimiter()
+	if err != nil {
+		return err
+	}
+	rateLimiter.Store(l)
+	return nil
+}
+
+// 根据 RateLimit 配置构建限流器，Default 作为最后一条 * 通配规则
+func newLimiter() (limiter.LimiterIface, error) {
+	setting := global.RateLimitSetting.Load()
+	keyFuncs, err := limiter.ParseKeyBy(setting.KeyBy)
+	if err != nil {
+		return nil, err
+	}
+	var l limiter.LimiterIface
+	if setting.Store.Type == "redis" {
+		if rateLimitRedis == nil {
+			rateLimitRedis = redis.NewClient(&redis.Options{
+				Addr:     setting.Store.Addr,
+				Password: setting.Store.Password,
+				DB:       setting.Store.DB,
+			})
+		}
+		l = limiter.NewStoreLimiter(limiter.NewRedisStore(rateLimitRedis), limiter.StoreLimiterOptions{
+			ClientLimiterOptions: limiter.ClientLimiterOptions{
+				MaxBuckets: setting.MaxBuckets,
+				BucketTTL:  setting.BucketTTL,
+			},
+			Prefix:  setting.Store.Prefix,
+			Timeout: setting.Store.Timeout,
+			OnStoreError: func(err error) {
+				global.Logger.Warnf("rate limit store unavailable, fallback to local buckets: %v", err)
+			},
+		}, keyFuncs...)
+	} else if len(keyFuncs) == 0 {
+		l = limiter.NewMethodLimiter()
+	} else {
+		l = limiter.NewClientLimiter(limiter.ClientLimiterOptions{
+			MaxBuckets: setting.MaxBuckets,
+			BucketTTL:  setting.BucketTTL,
+		}, keyFuncs...)
+	}
+	for _, rule := range setting.Rules {
+		l.AddBuckets(limiter.LimiterBucketRule{
+			Method:       rule.Method,
+			Key:          rule.Path,
+			FillInterval: rule.FillInterval,
+			Capacity:     rule.Capacity,
+			Quantum:      rule.Quantum,
+		})
+	}
+	return l.AddBuckets(limiter.LimiterBucketRule{
+		Key:          "*",
+		FillInterval: setting.Default.FillInterval,
+		Capacity:     setting.Default.Capacity,
+		Quantum:      setting.Default.Quantum,
+	}), nil
+}
+
+func NewRouter() (*gin.Engine, error) {
 	r := gin.New()
+	//只信任配置中的代理传来的 X-Forwarded-For，未配置时直接使用连接的对端地址
+	if err := r.SetTrustedProxies(global.ServerSetting.TrustedProxies); err != nil {
+		global.Logger.Errorf("r.SetTrustedProxies err: %v", err)
+	}
+	//探针接口注册在其他中间件之前，不受限流影响也不写访问日志
+	healthz := api.NewHealth(global.HealthChecker)
+	r.GET("/healthz", healthz.Liveness)
+	r.GET("/readyz", healthz.Readiness)
+
+	r.Use(middleware.RequestID())
+	if global.TracingSetting.Enable {
+		r.Use(middleware.Tracing())
+	}
+	if global.MetricsSetting.Enable {
+		r.Use(middleware.Metrics())
+		r.GET(global.MetricsSetting.Path, gin.WrapH(metrics.Handler()))
+	}
 	if global.ServerSetting.RunMode == "debug" {
 		r.Use(gin.Logger())
 		r.Use(gin.Recovery())
@@ -34,19 +115,38 @@ func NewRouter() *gin.Engine {
 		r.Use(gin.Recovery())
 	}
 
-	r.Use(middleware.RateLimiter(methodLimiters))
+	l, err := newLimiter()
+	if err != nil {
+		return nil, err
+	}
+	rateLimiter = limiter.NewDynamicLimiter(l)
+	r.Use(middleware.RateLimiter(rateLimiter))
 	r.Use(middleware.ContextTimeout(60 * time.Second))
 	r.Use(middleware.Translations())
 
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 	r.GET("/auth", api.GetAuth)
+	r.GET("/errcodes", api.NewErrCode().List)
+	if global.OIDCSetting.Enable {
+		oidc := api.NewOIDC()
+		r.GET("/auth/oidc/login", oidc.Login)
+		r.GET("/auth/oidc/callback", oidc.Callback)
+	}
 	article := v1.NewArticle()
 	tag := v1.NewTag()
 
 	upload := api.NewUpload()
 	r.POST("/upload/file", upload.UploadFile)
 	//文件服务只有提供静态资源的访问，才能在外部请求本项目HttpServer时同时提供静态资源的访问
-	r.StaticFS("/static", http.Dir(global.AppSetting.UploadSavePath))
+	r.StaticFS("/static", http.Dir(global.AppSetting.Load().UploadSavePath))
+	logLevel := api.NewLogLevel()
+	admin := r.Group("/admin")
+	admin.Use(middleware.JWT(), middleware.Admin())
+	{
+		admin.GET("/log/level", logLevel.Get)
+		admin.PUT("/log/level", logLevel.Update)
+	}
+
 	apiv1 := r.Group("/api/v1")
 	apiv1.Use(middleware.JWT())
 	{
@@ -63,5 +163,5 @@ func NewRouter() *gin.Engine {
 		apiv1.GET("/articles/:id", article.Get)
 		apiv1.GET("/articles", article.List)
 	}
-	return r
+	return r, nil
 }
@@ -1,28 +1,39 @@
 package service
 
 type CountArticleRequest struct {
-	Name  string `form:"name" binding:"max=100"`
+	TagID uint32 `form:"tag_id" binding:"omitempty,gte=1"`
 	State uint8  `form:"state,default=1" binding:"oneof=0 1"`
 }
 
 type ArticleListRequest struct {
-	Name  string `form:"name" binding:"max=100"`
+	TagID uint32 `form:"tag_id" binding:"omitempty,gte=1"`
 	State uint8  `form:"state,default=1" binding:"oneof=0 1"`
 }
 
 type CreateArticleRequest struct {
-	Name      string `form:"name" binding:"required,min=3,max=100"`
-	CreatedBy string `form:"created_by" binding:"required,min=3,max=100"`
-	State     string `form:"state,default=1" binding:"oneof=0 1"`
+	TagIDs        []uint32 `form:"tag_ids" binding:"required,tag_exists,dive,gte=1"`
+	Title         string   `form:"title" binding:"required,min=2,max=100"`
+	Slug          string   `form:"slug" binding:"omitempty,max=100,slug"`
+	Desc          string   `form:"desc" binding:"required,min=2,max=255"`
+	Content       string   `form:"content" binding:"required,min=2,safe_html"`
+	CoverImageUrl string   `form:"cover_image_url" binding:"required,max=255,safe_url"`
+	CreatedBy     string   `form:"created_by" binding:"required,min=2,max=100"`
+	State         uint8    `form:"state,default=1" binding:"oneof=0 1"`
 }
 
+// 除 ID 和修改人外均为可选，但至少要修改一项，见 updateArticleStructLevel
 type UpdateArticleRequest struct {
-	ID         string `form:"id" binding:"required,gte=1"`
-	Name       string `form:"name" binding:"required,min=3,max=100"`
-	State      string `form:"state,default=1" binding:"oneof=0 1"`
-	ModifiedBy string `form:"modified_by" binding:"required,min=3,max=100"`
+	ID            uint32   `form:"id" binding:"required,gte=1"`
+	TagIDs        []uint32 `form:"tag_ids" binding:"omitempty,tag_exists,dive,gte=1"`
+	Title         string   `form:"title" binding:"omitempty,min=2,max=100"`
+	Slug          string   `form:"slug" binding:"omitempty,max=100,slug"`
+	Desc          string   `form:"desc" binding:"omitempty,min=2,max=255"`
+	Content       string   `form:"content" binding:"omitempty,min=2,safe_html"`
+	CoverImageUrl string   `form:"cover_image_url" binding:"omitempty,max=255,safe_url"`
+	State         *uint8   `form:"state" binding:"omitempty,oneof=0 1"`
+	ModifiedBy    string   `form:"modified_by" binding:"required,min=2,max=100"`
 }
 
 type DeleteArticleRequest struct {
-	ID string `form:"id" binding:"required,gte=1"`
+	ID uint32 `form:"id" binding:"required,gte=1"`
 }
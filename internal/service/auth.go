@@ -1,6 +1,6 @@
 package service
 
-import "errors"
+import "fmt"
 
 //用于接口入参的校验
 type AuthRequest struct {
@@ -8,7 +8,9 @@ type AuthRequest struct {
 	AppSecret string `form:"app_secret" binding:"required"`
 }
 
-func (svc *Service) CheckAuth(param *AuthRequest) error {
+func (svc *Service) CheckAuth(param *AuthRequest) (err error) {
+	svc, span := svc.start("CheckAuth")
+	defer func() { end(span, err) }()
 	auth, err := svc.dao.GetAuth(param.AppKey, param.AppSecret)
 	if err != nil {
 		return err
@@ -17,5 +19,5 @@ func (svc *Service) CheckAuth(param *AuthRequest) error {
 		return nil
 	}
 
-	return errors.New("auth info does not exist.")
+	return fmt.Errorf("auth info: %w", ErrNotFound)
 }
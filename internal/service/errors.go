@@ -0,0 +1,15 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/ludyyy-lu/goBlogService/internal/dao"
+)
+
+// 接口层据此区分业务错误与内部错误，通过 errors.Is 判断
+var (
+	ErrNotFound    = dao.ErrNotFound
+	ErrDuplicate   = dao.ErrDuplicate
+	ErrConflict    = dao.ErrConflict
+	ErrInvalidFile = errors.New("invalid file")
+)
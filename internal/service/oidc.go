@@ -0,0 +1,56 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/ludyyy-lu/goBlogService/global"
+	"github.com/ludyyy-lu/goBlogService/internal/model"
+	"github.com/ludyyy-lu/goBlogService/pkg/oidc"
+)
+
+type OIDCCallbackRequest struct {
+	Code  string `form:"code" binding:"required"`
+	State string `form:"state" binding:"required"`
+}
+
+// 将 IdP 返回的外部身份映射为本地认证信息，未绑定时按配置自动创建
+func (svc *Service) OIDCSignIn(claims *oidc.IDTokenClaims) (_ model.Auth, err error) {
+	svc, span := svc.start("OIDCSignIn")
+	defer func() { end(span, err) }()
+	identity, err := svc.dao.GetUserIdentity(claims.Issuer, claims.Subject)
+	if err != nil {
+		return model.Auth{}, err
+	}
+	name := claims.Name
+	if name == "" {
+		name = claims.PreferredUsername
+	}
+	if identity.Model != nil && identity.ID > 0 {
+		if identity.Email != claims.Email || identity.Name != name {
+			if err := svc.dao.UpdateUserIdentity(identity.ID, claims.Email, name); err != nil {
+				return model.Auth{}, err
+			}
+		}
+		auth, err := svc.dao.GetAuthByID(identity.AuthID)
+		if err != nil {
+			return auth, err
+		}
+		if auth.Model == nil || auth.ID == 0 {
+			return auth, errors.New("auth info of the identity does not exist.")
+		}
+		return auth, nil
+	}
+
+	if !global.OIDCSetting.AutoCreate {
+		return model.Auth{}, errors.New("identity is not linked to any local user.")
+	}
+	appKey, err := oidc.RandomString(12)
+	if err != nil {
+		return model.Auth{}, err
+	}
+	appSecret, err := oidc.RandomString(32)
+	if err != nil {
+		return model.Auth{}, err
+	}
+	return svc.dao.CreateUserIdentity(claims.Issuer, claims.Subject, claims.Email, name, "oidc_"+appKey[:15], appSecret)
+}
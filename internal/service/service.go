@@ -2,9 +2,12 @@ package service
 
 import (
 	"context"
+	"errors"
 
 	"github.com/ludyyy-lu/goBlogService/global"
 	"github.com/ludyyy-lu/goBlogService/internal/dao"
+	"github.com/ludyyy-lu/goBlogService/pkg/tracer"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type Service struct {
@@ -14,6 +17,20 @@ type Service struct {
 
 func New(ctx context.Context) Service {
 	svc := Service{ctx: ctx}
-	svc.dao = dao.New(global.DBEngine)
+	svc.dao = dao.New(global.DBEngine).WithContext(ctx)
 	return svc
 }
+
+// 为业务方法创建 span，返回的 Service 绑定到该 span，Dao 的 span 会挂在它下面
+func (svc *Service) start(name string) (*Service, trace.Span) {
+	ctx, span := tracer.Start(svc.ctx, "service."+name)
+	return &Service{ctx: ctx, dao: svc.dao.WithContext(ctx)}, span
+}
+
+// 业务上的不存在由接口层处理，不标记为失败
+func end(span trace.Span, err error) {
+	if errors.Is(err, ErrNotFound) {
+		err = nil
+	}
+	tracer.End(span, err)
+}
@@ -1,6 +1,9 @@
 package service
 
 import (
+	"errors"
+	"fmt"
+
 	"github.com/ludyyy-lu/goBlogService/internal/model"
 	"github.com/ludyyy-lu/goBlogService/pkg/app"
 )
@@ -16,14 +19,14 @@ type TagListRequest struct {
 }
 
 type CreateTagRequest struct {
-	Name      string `form:"name" binding:"required,min=3,max=100"`
+	Name      string `form:"name" binding:"required,min=3,max=100,tag_name_unique"`
 	CreatedBy string `form:"created_by" binding:"required,min=3,max=100"`
 	State     uint8  `form:"state,default=1" binding:"oneof=0 1"`
 }
 
 type UpdateTagRequest struct {
 	ID         uint32 `form:"id" binding:"required,gte=1"`
-	Name       string `form:"name" binding:"required,min=3,max=100"`
+	Name       string `form:"name" binding:"omitempty,min=3,max=100,tag_name_unique"`
 	State      uint8  `form:"state,default=1" binding:"oneof=0 1"`
 	ModifiedBy string `form:"modified_by" binding:"required,min=3,max=100"`
 }
@@ -32,22 +35,62 @@ type DeleteTagRequest struct {
 	ID uint32 `form:"id" binding:"required,gte=1"`
 }
 
-func (svc *Service) CountTag(param *CountTagRequest) (int, error) {
+func (svc *Service) CountTag(param *CountTagRequest) (count int, err error) {
+	svc, span := svc.start("CountTag")
+	defer func() { end(span, err) }()
 	return svc.dao.CountTag(param.Name, param.State)
 }
 
-func (svc *Service) GetTagList(param *TagListRequest, pager *app.Pager) ([]*model.Tag, error) {
+func (svc *Service) GetTagList(param *TagListRequest, pager *app.Pager) (tags []*model.Tag, err error) {
+	svc, span := svc.start("GetTagList")
+	defer func() { end(span, err) }()
 	return svc.dao.GetTagList(param.Name, param.State, pager.Page, pager.PageSize)
 }
 
-func (svc *Service) CreateTag(param *CreateTagRequest) error {
+func (svc *Service) CreateTag(param *CreateTagRequest) (err error) {
+	svc, span := svc.start("CreateTag")
+	defer func() { end(span, err) }()
+	if err := svc.checkTagName(0, param.Name); err != nil {
+		return err
+	}
 	return svc.dao.CreateTag(param.Name, param.State, param.CreatedBy)
 }
 
-func (svc *Service) UpdateTag(param *UpdateTagRequest) error {
+func (svc *Service) UpdateTag(param *UpdateTagRequest) (err error) {
+	svc, span := svc.start("UpdateTag")
+	defer func() { end(span, err) }()
+	if _, err := svc.dao.GetTag(param.ID); err != nil {
+		return fmt.Errorf("tag %d: %w", param.ID, err)
+	}
+	if err := svc.checkTagName(param.ID, param.Name); err != nil {
+		return err
+	}
 	return svc.dao.UpdateTag(param.ID, param.Name, param.State, param.ModifiedBy)
 }
 
-func (svc *Service) DeleteTag(param *DeleteTagRequest) error {
-	return svc.dao.DeleteTag(param.ID) 
+func (svc *Service) DeleteTag(param *DeleteTagRequest) (err error) {
+	svc, span := svc.start("DeleteTag")
+	defer func() { end(span, err) }()
+	if err := svc.dao.DeleteTag(param.ID); err != nil {
+		return fmt.Errorf("tag %d: %w", param.ID, err)
+	}
+	return nil
+}
+
+// 标签名称不能与其他未删除的标签重复；校验通过后到写入前仍可能有并发请求使用了同一名称，因此这里仍需检查
+func (svc *Service) checkTagName(id uint32, name string) error {
+	if name == "" {
+		return nil
+	}
+	tag, err := svc.dao.GetTagByName(name)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if tag.Model != nil && tag.ID != id {
+		return fmt.Errorf("tag name %q: %w", name, ErrDuplicate)
+	}
+	return nil
 }
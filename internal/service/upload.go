@@ -2,10 +2,12 @@ package service
 
 import (
 	"errors"
+	"fmt"
 	"mime/multipart"
 	"os"
 
 	"github.com/ludyyy-lu/goBlogService/global"
+	"github.com/ludyyy-lu/goBlogService/pkg/metrics"
 	"github.com/ludyyy-lu/goBlogService/pkg/upload"
 )
 
@@ -14,12 +16,14 @@ type FileInfo struct {
 	AccessUrl string
 }
 
-func (svc *Service) UploadFile(fileType upload.FileType, file multipart.File, fileHeader *multipart.FileHeader) (*FileInfo, error) {
+func (svc *Service) UploadFile(fileType upload.FileType, file multipart.File, fileHeader *multipart.FileHeader) (_ *FileInfo, err error) {
+	_, span := svc.start("UploadFile")
+	defer func() { end(span, err) }()
 	fileName := upload.GetFileName(fileHeader.Filename)
 	uploadSavePath := upload.GetSavePath()
 	dst := uploadSavePath + "/" + fileName
 	if !upload.CheckContainExt(fileType, fileName) {
-		return nil, errors.New("file suffix is not supported")
+		return nil, fmt.Errorf("file suffix is not supported: %w", ErrInvalidFile)
 	}
 	if upload.CheckSavePath(uploadSavePath) {
 		err := upload.CreateSavePath(uploadSavePath, os.ModePerm)
@@ -28,7 +32,7 @@ func (svc *Service) UploadFile(fileType upload.FileType, file multipart.File, fi
 		}
 	}
 	if upload.CheckMaxSize(fileType, file) {
-		return nil, errors.New("exceeded maximum file limit")
+		return nil, fmt.Errorf("exceeded maximum file limit: %w", ErrInvalidFile)
 	}
 	if upload.CheckPermission(uploadSavePath) {
 		return nil, errors.New("insufficient file permissions")
@@ -36,6 +40,7 @@ func (svc *Service) UploadFile(fileType upload.FileType, file multipart.File, fi
 	if err := upload.SaveFile(fileHeader, dst); err != nil {
 		return nil, err
 	}
-	accessUrl := global.AppSetting.UploadServerUrl + "/" + fileName
+	metrics.UploadBytesTotal.WithLabelValues(fileType.String()).Add(float64(fileHeader.Size))
+	accessUrl := global.AppSetting.Load().UploadServerUrl + "/" + fileName
 	return &FileInfo{Name: fileName, AccessUrl: accessUrl}, nil
 }
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/ludyyy-lu/goBlogService/global"
+	"github.com/ludyyy-lu/goBlogService/internal/dao"
+	"github.com/ludyyy-lu/goBlogService/pkg/i18n"
+	"github.com/ludyyy-lu/goBlogService/pkg/validation"
+)
+
+// 注册通用规则和依赖业务数据的规则，需在数据库初始化之后调用
+func RegisterValidations(v *validator.Validate) error {
+	if err := validation.Register(v, validation.DefaultRules()...); err != nil {
+		return err
+	}
+	err := validation.Register(v,
+		validation.Rule{
+			Tag:     "tag_name_unique",
+			FuncCtx: tagNameUnique,
+			Messages: map[string]string{
+				i18n.ZH:   "{0}已被其他标签使用",
+				i18n.EN:   "{0} is already used by another tag",
+				i18n.ZHTW: "{0}已被其他標籤使用",
+			},
+		},
+		validation.Rule{
+			Tag:     "tag_exists",
+			FuncCtx: tagExists,
+			Messages: map[string]string{
+				i18n.ZH:   "{0}中包含不存在的标签",
+				i18n.EN:   "{0} contains tags that do not exist",
+				i18n.ZHTW: "{0}中包含不存在的標籤",
+			},
+		},
+		validation.Rule{
+			Tag: "update_fields",
+			Messages: map[string]string{
+				i18n.ZH:   "至少需要修改一项内容",
+				i18n.EN:   "at least one field must be changed",
+				i18n.ZHTW: "至少需要修改一項內容",
+			},
+		},
+	)
+	if err != nil {
+		return err
+	}
+	v.RegisterStructValidation(updateArticleStructLevel, UpdateArticleRequest{})
+	return nil
+}
+
+// 标签名称不能与其他标签重复，更新时通过同一结构体中的 ID 字段排除自身；
+// 查询失败时视为不通过，避免在数据库异常时放过重复的名称
+func tagNameUnique(ctx context.Context, fl validator.FieldLevel) bool {
+	name := fl.Field().String()
+	if name == "" {
+		return true
+	}
+	var id uint32
+	if f := reflect.Indirect(fl.Parent()).FieldByName("ID"); f.IsValid() && f.CanUint() {
+		id = uint32(f.Uint())
+	}
+	tag, err := dao.New(global.DBEngine).WithContext(ctx).GetTagByName(name)
+	if errors.Is(err, ErrNotFound) {
+		return true
+	}
+	if err != nil {
+		global.Logger.WithContext(ctx).WithError(err).Errorf("validation tag_name_unique err: %v", err)
+		return false
+	}
+	return tag.Model == nil || tag.ID == id
+}
+
+// 支持单个标签 ID 或标签 ID 列表，查询失败时视为不通过
+func tagExists(ctx context.Context, fl validator.FieldLevel) bool {
+	var ids []uint32
+	field := fl.Field()
+	switch field.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < field.Len(); i++ {
+			ids = append(ids, uint32(field.Index(i).Uint()))
+		}
+	default:
+		ids = append(ids, uint32(field.Uint()))
+	}
+	slices.Sort(ids)
+	ids = slices.Compact(ids)
+	if len(ids) == 0 {
+		return true
+	}
+	count, err := dao.New(global.DBEngine).WithContext(ctx).CountTagByIDs(ids)
+	if err != nil {
+		global.Logger.WithContext(ctx).WithError(err).Errorf("validation tag_exists err: %v", err)
+		return false
+	}
+	return count == len(ids)
+}
+
+// 更新文章时至少要修改一项内容，仅修改状态也算
+func updateArticleStructLevel(sl validator.StructLevel) {
+	param := sl.Current().Interface().(UpdateArticleRequest)
+	if param.Title == "" && param.Slug == "" && param.Desc == "" && param.Content == "" &&
+		param.CoverImageUrl == "" && len(param.TagIDs) == 0 && param.State == nil {
+		sl.ReportError(param.Title, "Title", "Title", "update_fields", "")
+	}
+}
@@ -1,41 +1,90 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
 	"log"
+	"net"
 	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
 	"github.com/ludyyy-lu/goBlogService/global"
 	"github.com/ludyyy-lu/goBlogService/internal/model"
 	"github.com/ludyyy-lu/goBlogService/internal/routers"
+	"github.com/ludyyy-lu/goBlogService/internal/service"
+	"github.com/ludyyy-lu/goBlogService/pkg/app"
+	"github.com/ludyyy-lu/goBlogService/pkg/health"
 	"github.com/ludyyy-lu/goBlogService/pkg/logger"
+	"github.com/ludyyy-lu/goBlogService/pkg/metrics"
 	"github.com/ludyyy-lu/goBlogService/pkg/setting"
+	"github.com/ludyyy-lu/goBlogService/pkg/tracer"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	"gopkg.in/natefinch/lumberjack.v2"
 )
 
+var (
+	port        string
+	runMode     string
+	config      string
+	checkConfig bool
+)
+
 // @termsOfService https://github.com/ludyyy-lu/goBlogService
 // go语言的程序执行顺序：全局变量初始化 -> init方法 -> main方法
 func init() {
-	err := setupSetting()
+	err := setupFlag()
+	if err != nil {
+		log.Fatalf("init.setupFlag err: %v", err)
+	}
+	err = setupSetting()
 	if err != nil {
 		log.Fatalf("init.setupSetting err: %v", err)
 	}
-	//实际上并没有error
+	//只校验配置，不初始化日志和数据库
+	if checkConfig {
+		if err := setting.Dump(os.Stdout, loadedSettings); err != nil {
+			log.Fatalf("init.setting.Dump err: %v", err)
+		}
+		os.Exit(0)
+	}
 	err = setupLogger()
 	if err != nil {
 		log.Fatalf("init.setupLogger err: %v", err)
 	}
+	err = setupTracer()
+	if err != nil {
+		log.Fatalf("init.setupTracer err: %v", err)
+	}
 	err = setupDBEngin()
 	if err != nil {
 		log.Fatalf("init.setupDBEngin err: %v", err)
 	}
+	setupHealthChecker()
+	err = setupValidator()
+	if err != nil {
+		log.Fatalf("init.setupValidator err: %v", err)
+	}
 }
 func main() {
 	global.Logger.Infof("%s: goHttpWeb-practice/%s", "ludy-lu", "blog-service")
 
 	gin.SetMode(global.ServerSetting.RunMode)
-	router := routers.NewRouter()
+	router, err := routers.NewRouter()
+	if err != nil {
+		log.Fatalf("routers.NewRouter err: %v", err)
+	}
+	watchSetting()
 	//设置已经映射好的配置和gin的运行模式
 	s := &http.Server{
 		Addr:           ":" + global.ServerSetting.HttpPort, //8000
@@ -44,55 +93,308 @@ func main() {
 		WriteTimeout:   global.ServerSetting.WriteTimeout,
 		MaxHeaderBytes: 1 << 20,
 	}
-	s.ListenAndServe()
+	go func() {
+		if err := s.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatalf("s.ListenAndServe err: %v", err)
+		}
+	}()
+
+	//等待中断信号，优雅地关闭服务器并写完缓冲中的日志
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+	global.Logger.Infof("shutting down server...")
+	//先让就绪探针失败，等待负载均衡摘除流量后再停止接收请求
+	global.HealthChecker.SetDraining()
+	time.Sleep(global.HealthSetting.ShutdownDelay)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := s.Shutdown(ctx); err != nil {
+		global.Logger.Errorf("server forced to shutdown: %v", err)
+	}
+	if tracerProvider != nil {
+		if err := tracerProvider.Shutdown(ctx); err != nil {
+			global.Logger.Errorf("tracerProvider.Shutdown err: %v", err)
+		}
+	}
+	if c, ok := global.AccessLogWriter.(io.Closer); ok {
+		_ = c.Close()
+	}
+	if err := global.Logger.Close(); err != nil {
+		log.Printf("global.Logger.Close err: %v", err)
+	}
 }
+
+// 命令行参数优先级最高，会覆盖环境变量和配置文件中的值
+func setupFlag() error {
+	flag.StringVar(&port, "port", "", "启动端口")
+	flag.StringVar(&runMode, "mode", "", "启动模式")
+	flag.StringVar(&config, "config", "configs/", "指定要使用的配置文件路径，多个路径用逗号分隔")
+	flag.BoolVar(&checkConfig, "check-config", false, "校验配置并输出生效的配置（屏蔽密钥）后退出")
+	flag.Parse()
+	return nil
+}
+
+// 所有配置区段，启动和热加载时都先完整读取到这里，再写入 global
+type settings struct {
+	Server    *setting.ServerSettingS
+	App       *setting.AppSettingS
+	Database  *setting.DatabaseSettingS
+	JWT       *setting.JWTSettingS
+	Email     *setting.EmailSettingS
+	OIDC      *setting.OIDCSettingS
+	RateLimit *setting.RateLimitSettingS
+	Log       *setting.LogSettingS
+	AccessLog *setting.AccessLogSettingS
+	Metrics   *setting.MetricsSettingS
+	Tracing   *setting.TracingSettingS
+	Health    *setting.HealthSettingS
+}
+
+// 热加载时用于重新读取配置
+var settingLoader *setting.Setting
+
+// 启动时读取的配置，用于 -check-config 输出
+var loadedSettings *settings
+
 func setupSetting() error {
-	setting, err := setting.NewSetting()
+	setting, err := setting.NewSetting(strings.Split(config, ",")...)
 	if err != nil {
 		return err
 	}
-	err = setting.ReadSection("Server", &global.ServerSetting)
-	if err != nil {
-		return err
+	if port != "" {
+		setting.Override("Server.HttpPort", port)
 	}
-	err = setting.ReadSection("App", &global.AppSetting)
-	if err != nil {
-		return err
+	if runMode != "" {
+		setting.Override("Server.RunMode", runMode)
 	}
-	err = setting.ReadSection("Database", &global.DatabaseSetting)
+	s, err := readSettings(setting)
 	if err != nil {
 		return err
 	}
-	err = setting.ReadSection("JWT", &global.JWTSetting)
+	global.ServerSetting = s.Server
+	global.AppSetting.Store(s.App)
+	global.DatabaseSetting = s.Database
+	global.JWTSetting = s.JWT
+	global.EmailSetting.Store(s.Email)
+	global.OIDCSetting = s.OIDC
+	global.RateLimitSetting.Store(s.RateLimit)
+	global.LogSetting.Store(s.Log)
+	global.AccessLogSetting = s.AccessLog
+	global.MetricsSetting = s.Metrics
+	global.TracingSetting = s.Tracing
+	global.HealthSetting = s.Health
+	settingLoader = setting
+	loadedSettings = s
+	return nil
+}
+
+// 读取并校验所有区段，返回的错误包含每个不合法的区段，便于一次改完
+func readSettings(loader *setting.Setting) (*settings, error) {
+	s := &settings{}
+	var errs []error
+	read := func(k string, v any) {
+		if err := loader.ReadSection(k, v); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	read("Server", &s.Server)
+	read("App", &s.App)
+	read("Database", &s.Database)
+	read("JWT", &s.JWT)
+	read("Email", &s.Email)
+	read("OIDC", &s.OIDC)
+	read("RateLimit", &s.RateLimit)
+	read("Log", &s.Log)
+	read("AccessLog", &s.AccessLog)
+	read("Metrics", &s.Metrics)
+	read("Tracing", &s.Tracing)
+	read("Health", &s.Health)
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func setupLogger() error {
+	var sinks []*logger.Sink
+	for i, sinkSetting := range global.LogSetting.Load().Sinks {
+		sink, err := newLogSink(strconv.Itoa(i), sinkSetting)
+		if err != nil {
+			return err
+		}
+		sinks = append(sinks, sink)
+	}
+	//未配置输出目标时沿用 App 中的日志文件
+	if len(sinks) == 0 {
+		sink, err := newLogSink("default", setting.LogSinkS{Type: "file"})
+		if err != nil {
+			return err
+		}
+		sinks = append(sinks, sink)
+	}
+	global.Logger = logger.NewLogger(sinks...).WithCaller(2)
+
+	if global.AccessLogSetting.Format == "combined" {
+		w, err := newLogWriter(global.AccessLogSetting.Sink)
+		if err != nil {
+			return err
+		}
+		global.AccessLogWriter = w
+	}
+
+	return applyLogLevels(global.LogSetting.Load())
+}
+
+// 先解析全部级别，全部合法后再应用，并清除新配置中已删除的组件级别
+func applyLogLevels(logSetting *setting.LogSettingS) error {
+	level := logger.LevelDebug
+	if logSetting.Level != "" {
+		var err error
+		level, err = logger.ParseLevel(logSetting.Level)
+		if err != nil {
+			return err
+		}
+	}
+	components := make(map[string]logger.Level, len(logSetting.Levels))
+	for component, l := range logSetting.Levels {
+		level, err := logger.ParseLevel(l)
+		if err != nil {
+			return err
+		}
+		components[component] = level
+	}
+
+	//未配置 Level 时恢复为 NewLogger 的默认级别，热加载时删除 Level 也会生效
+	global.Logger.SetLevel(level)
+	_, current := global.Logger.Levels()
+	for component := range current {
+		if _, ok := components[component]; !ok {
+			global.Logger.ResetComponentLevel(component)
+		}
+	}
+	for component, level := range components {
+		global.Logger.SetComponentLevel(component, level)
+	}
+	return nil
+}
+
+// name 为输出目标在配置中的序号，用于区分各异步输出目标的丢弃指标
+func newLogSink(name string, sinkSetting setting.LogSinkS) (*logger.Sink, error) {
+	format, err := logger.ParseFormat(sinkSetting.Format)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	err = setting.ReadSection("Email",&global.EmailSetting)
+	w, err := newLogWriter(sinkSetting)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	global.JWTSetting.Expire *= time.Second //有效时间
-	global.ServerSetting.ReadTimeout *= time.Second
-	global.ServerSetting.WriteTimeout *= time.Second
+	if sinkSetting.Async {
+		policy := logger.OverflowPolicy(sinkSetting.Overflow)
+		if policy != "" && policy != logger.OverflowBlock && policy != logger.OverflowDrop {
+			return nil, fmt.Errorf("unknown log overflow policy %q", sinkSetting.Overflow)
+		}
+		aw := logger.NewAsyncWriter(w, logger.AsyncOptions{
+			BufferSize: sinkSetting.BufferSize,
+			BatchSize:  sinkSetting.BatchSize,
+			Policy:     policy,
+		})
+		if err := metrics.RegisterLogDropped(name, aw.Dropped); err != nil {
+			return nil, err
+		}
+		w = aw
+	}
+	return logger.NewSink(w, format), nil
+}
 
-	return nil
+func newLogWriter(sinkSetting setting.LogSinkS) (io.Writer, error) {
+	switch sinkSetting.Type {
+	case "", "file":
+		fileName := sinkSetting.Filename
+		if fileName == "" {
+			appSetting := global.AppSetting.Load()
+			fileName = appSetting.LogSavePath + "/" + appSetting.LogFileName + appSetting.LogFileExt
+		}
+		maxSize, maxAge := sinkSetting.MaxSize, sinkSetting.MaxAge
+		if maxSize <= 0 {
+			maxSize = 600
+		}
+		if maxAge <= 0 {
+			maxAge = 10
+		}
+		return &lumberjack.Logger{
+			Filename:   fileName,
+			MaxSize:    maxSize,
+			MaxAge:     maxAge,
+			MaxBackups: sinkSetting.MaxBackups,
+			Compress:   sinkSetting.Compress,
+			LocalTime:  true,
+		}, nil
+	//包装一层，避免关闭日志时把标准输出也关闭
+	case "stdout":
+		return struct{ io.Writer }{os.Stdout}, nil
+	case "stderr":
+		return struct{ io.Writer }{os.Stderr}, nil
+	case "syslog":
+		return logger.NewSyslogWriter(sinkSetting.Network, sinkSetting.Address, sinkSetting.Tag)
+	}
+	return nil, fmt.Errorf("unknown log sink type %q", sinkSetting.Type)
 }
 
-func setupLogger() error {
-	fileName := global.AppSetting.LogSavePath + "/" + global.AppSetting.LogFileName + global.AppSetting.LogFileExt
-	global.Logger = logger.NewLogger(&lumberjack.Logger{
-		Filename:  fileName,
-		MaxSize:   600,
-		MaxAge:    10,
-		LocalTime: true,
-	}, "", log.LstdFlags).WithCaller(2)
-	return nil
+var tracerProvider *sdktrace.TracerProvider
+
+func setupTracer() error {
+	if !global.TracingSetting.Enable {
+		return nil
+	}
+	var err error
+	tracerProvider, err = tracer.NewTracerProvider(tracer.Config{
+		ServiceName: global.TracingSetting.ServiceName,
+		Exporter:    global.TracingSetting.Exporter,
+		Endpoint:    global.TracingSetting.Endpoint,
+		Insecure:    global.TracingSetting.Insecure,
+		SampleRatio: global.TracingSetting.SampleRatio,
+	})
+	return err
 }
+
 func setupDBEngin() error {
 	var err error
 	global.DBEngine, err = model.NewDBEngine(global.DatabaseSetting)
 	if err != nil {
 		return err
 	}
+	if global.MetricsSetting.Enable {
+		err = metrics.RegisterDBStats(global.DBEngine.DB(), global.DatabaseSetting.DBName)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func setupHealthChecker() {
+	global.HealthChecker = health.NewChecker(global.HealthSetting.CheckTimeout).
+		Register("database", health.DBPing(global.DBEngine.DB())).
+		Register("upload_dir", health.DirWritable(func() string {
+			return global.AppSetting.Load().UploadSavePath
+		}))
+	if global.HealthSetting.CheckSMTP {
+		emailSetting := global.EmailSetting.Load()
+		address := net.JoinHostPort(emailSetting.Host, strconv.Itoa(emailSetting.Port))
+		global.HealthChecker.Register("smtp", health.TCPDial(address))
+	}
+}
+
+func setupValidator() error {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return nil
+	}
+	if err := service.RegisterValidations(v); err != nil {
+		return err
+	}
+	binding.Validator = app.NewContextValidator(v)
 	return nil
 }
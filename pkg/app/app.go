@@ -5,6 +5,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/ludyyy-lu/goBlogService/pkg/errcode"
+	"github.com/ludyyy-lu/goBlogService/pkg/i18n"
 )
 
 type Response struct {
@@ -40,13 +41,43 @@ func (r *Response) ToResponseList(list any, totalRows int) {
 }
 
 func (r *Response) ToErrorResponse(err *errcode.Error) {
+	if r.wantsProblem() {
+		r.toProblem(err, nil)
+		return
+	}
 	response := gin.H{
-		"code":err.Code(),
-		"msg":err.Msg(),
+		"code": err.Code(),
+		"key":  err.Key(),
+		"msg":  err.LocaleMsg(r.locale()),
 	}
 	details := err.Details()
 	if len(details) > 0 {
 		response["details"] = details
 	}
-	r.Ctx.JSON(err.StatusCode(),response)
-}
\ No newline at end of file
+	r.Ctx.JSON(err.StatusCode(), response)
+}
+
+// 入参校验失败时的响应，按字段列出未通过的规则及其信息
+func (r *Response) ToValidErrorResponse(errs ValidErrors) {
+	err := errcode.InvalidParams.WithDetails(errs.Errors()...)
+	if r.wantsProblem() {
+		r.toProblem(err, errs)
+		return
+	}
+	response := gin.H{
+		"code":    err.Code(),
+		"key":     err.Key(),
+		"msg":     err.LocaleMsg(r.locale()),
+		"details": err.Details(),
+		"fields":  errs.Fields(),
+	}
+	r.Ctx.JSON(err.StatusCode(), response)
+}
+
+// 优先使用 Translations 中间件的协商结果，限流等先于它执行的中间件则在此协商
+func (r *Response) locale() string {
+	if locale := r.Ctx.GetString("locale"); locale != "" {
+		return locale
+	}
+	return i18n.FromRequest(r.Ctx.Request)
+}
@@ -0,0 +1,59 @@
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ludyyy-lu/goBlogService/pkg/errcode"
+)
+
+func TestToErrorResponseFormatsArgs(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		name    string
+		header  string
+		accept  string
+		problem bool
+		want    string
+	}{
+		{"default locale", "", "", false, "请求过多，请 30 秒后重试"},
+		{"english", "", "en-US,en;q=0.9", false, "Too many requests, retry in 30 seconds"},
+		{"traditional chinese", "zh-TW", "", false, "請求過多，請 30 秒後重試"},
+		{"problem title", "", "en", true, "Too many requests, retry in 30 seconds"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/auth", nil)
+			if tt.header != "" {
+				c.Request.Header.Set("locale", tt.header)
+			}
+			if tt.accept != "" {
+				c.Request.Header.Set("Accept-Language", tt.accept)
+			}
+			if tt.problem {
+				c.Request.Header.Set("Accept", ProblemContentType)
+			}
+			NewResponse(c).ToErrorResponse(errcode.TooManyRequests.WithArgs(30))
+
+			var body struct {
+				Msg   string `json:"msg"`
+				Title string `json:"title"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			got := body.Msg
+			if tt.problem {
+				got = body.Title
+			}
+			if got != tt.want {
+				t.Errorf("message = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
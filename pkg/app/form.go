@@ -1,21 +1,30 @@
 package app
 
 import (
+	"encoding/json"
+	"errors"
+	"reflect"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	ut "github.com/go-playground/universal-translator"
 	val "github.com/go-playground/validator/v10"
 )
 
 // 接口校验
 type ValidError struct {
-	Key     string
-	Message string
+	Key     string `json:"field"`           //字段名，取自 form 或 json 标签
+	Rule    string `json:"rule"`            //未通过的校验规则，请求体无法解析时为 decode
+	Param   string `json:"param,omitempty"` //规则的参数，如 max=100 中的 100
+	Message string `json:"message"`
 }
 
 type ValidErrors []*ValidError
 
+// 请求体或参数无法解析时使用的规则名
+const RuleDecode = "decode"
+
 func (v *ValidError) Error() string {
 	return v.Message
 }
@@ -32,23 +41,97 @@ func (v ValidErrors) Errors() []string {
 	return errs
 }
 
+// 按字段分组，无法对应到字段的错误归在空字符串下
+func (v ValidErrors) Fields() map[string][]*ValidError {
+	fields := make(map[string][]*ValidError, len(v))
+	for _, err := range v {
+		fields[err.Key] = append(fields[err.Key], err)
+	}
+	return fields
+}
+
 func BindAndValid(c *gin.Context, v any) (bool, ValidErrors) {
 	var errs ValidErrors
-	err := c.ShouldBind(v)
+	var err error
+	if cv, ok := binding.Validator.(*ContextValidator); ok {
+		err = cv.bindCtx(c, v)
+	} else {
+		err = c.ShouldBind(v)
+	}
 	if err != nil {
-		v := c.Value("trans")
-		trans, _ := v.(ut.Translator)
-		verrs, ok := err.(val.ValidationErrors)
-		if !ok {
-			return false, errs
+		value := c.Value("trans")
+		trans, _ := value.(ut.Translator)
+		var verrs val.ValidationErrors
+		if !errors.As(err, &verrs) {
+			return false, append(errs, decodeError(err))
 		}
-		for key, value := range verrs.Translate(trans) {
+		t := reflect.TypeOf(v)
+		for _, fe := range verrs {
 			errs = append(errs, &ValidError{
-				Key:     key,
-				Message: value,
+				Key:     fieldName(t, fe.StructNamespace()),
+				Rule:    fe.Tag(),
+				Param:   fe.Param(),
+				Message: fe.Translate(trans),
 			})
 		}
 		return false, errs
 	}
 	return true, nil
 }
+
+// JSON 类型不匹配时能定位到字段，其余解析错误（格式错误、表单类型转换失败等）不对应具体字段
+func decodeError(err error) *ValidError {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return &ValidError{
+			Key:     typeErr.Field,
+			Rule:    RuleDecode,
+			Param:   typeErr.Type.String(),
+			Message: typeErr.Error(),
+		}
+	}
+	return &ValidError{Rule: RuleDecode, Message: err.Error()}
+}
+
+// 将 TagListRequest.Name 这样的结构体路径转换为请求中使用的字段名，如 name
+func fieldName(t reflect.Type, namespace string) string {
+	parts := strings.Split(namespace, ".")
+	if len(parts) > 1 {
+		parts = parts[1:]
+	}
+	names := make([]string, 0, len(parts))
+	for _, part := range parts {
+		for t != nil && (t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Map) {
+			t = t.Elem()
+		}
+		//切片元素形如 Tags[0]，按字段名查找后再拼回下标
+		name, index, _ := strings.Cut(part, "[")
+		if index != "" {
+			index = "[" + index
+		}
+		if t == nil || t.Kind() != reflect.Struct {
+			names = append(names, part)
+			t = nil
+			continue
+		}
+		f, ok := t.FieldByName(name)
+		if !ok {
+			names = append(names, part)
+			t = nil
+			continue
+		}
+		names = append(names, tagName(f)+index)
+		t = f.Type
+	}
+	return strings.Join(names, ".")
+}
+
+func tagName(f reflect.StructField) string {
+	for _, key := range []string{"form", "json"} {
+		name, _, _ := strings.Cut(f.Tag.Get(key), ",")
+		if name != "" && name != "-" {
+			return name
+		}
+	}
+	return f.Name
+}
@@ -0,0 +1,255 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	val "github.com/go-playground/validator/v10"
+	"github.com/ludyyy-lu/goBlogService/pkg/errcode"
+)
+
+type ctxKey struct{}
+
+type nameRequest struct {
+	Name string `form:"name" binding:"required,allowed_name"`
+}
+
+// 规则只允许请求上下文中给出的名称，用于确认校验拿到的是请求的上下文
+func TestBindAndValidPassesRequestContext(t *testing.T) {
+	v := val.New()
+	v.SetTagName("binding")
+	err := v.RegisterValidationCtx("allowed_name", func(ctx context.Context, fl val.FieldLevel) bool {
+		allowed, _ := ctx.Value(ctxKey{}).(string)
+		return fl.Field().String() == allowed
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	prev := binding.Validator
+	binding.Validator = NewContextValidator(v)
+	t.Cleanup(func() { binding.Validator = prev })
+
+	tests := []struct {
+		name  string
+		query string
+		valid bool
+		rule  string
+	}{
+		{"allowed", "name=go", true, ""},
+		{"rejected by ctx rule", "name=rust", false, "allowed_name"},
+		{"missing", "", false, "required"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			req := httptest.NewRequest("GET", "/?"+tt.query, nil)
+			c.Request = req.WithContext(context.WithValue(req.Context(), ctxKey{}, "go"))
+			valid, errs := BindAndValid(c, &nameRequest{})
+			if valid != tt.valid {
+				t.Fatalf("valid = %v, want %v (%v)", valid, tt.valid, errs)
+			}
+			if !tt.valid && (len(errs) != 1 || errs[0].Rule != tt.rule || errs[0].Key != "name") {
+				t.Errorf("errs = %+v, want name/%s", errs[0], tt.rule)
+			}
+		})
+	}
+}
+
+// 规则统计执行次数，用于确认 BindAndValid 只校验一次，其他绑定方式仍会校验
+func TestContextValidatorValidatesOnce(t *testing.T) {
+	var calls int
+	v := val.New()
+	v.SetTagName("binding")
+	err := v.RegisterValidationCtx("allowed_name", func(ctx context.Context, fl val.FieldLevel) bool {
+		calls++
+		return fl.Field().String() == "go"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	prev := binding.Validator
+	binding.Validator = NewContextValidator(v)
+	t.Cleanup(func() { binding.Validator = prev })
+
+	tests := []struct {
+		name  string
+		bind  func(c *gin.Context) bool
+		query string
+		valid bool
+	}{
+		{"BindAndValid", func(c *gin.Context) bool { valid, _ := BindAndValid(c, &nameRequest{}); return valid }, "name=go", true},
+		{"ShouldBind", func(c *gin.Context) bool { return c.ShouldBind(&nameRequest{}) == nil }, "name=go", true},
+		{"ShouldBind rejects", func(c *gin.Context) bool { return c.ShouldBind(&nameRequest{}) == nil }, "name=rust", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls = 0
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest("GET", "/?"+tt.query, nil)
+			if valid := tt.bind(c); valid != tt.valid {
+				t.Errorf("valid = %v, want %v", valid, tt.valid)
+			}
+			if calls != 1 {
+				t.Errorf("rule ran %d times, want 1", calls)
+			}
+		})
+	}
+}
+
+type authorRequest struct {
+	Email string `json:"email" binding:"omitempty,email"`
+}
+
+type itemRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+type postRequest struct {
+	Title  string        `json:"title" binding:"required,max=5"`
+	Count  int           `json:"count"`
+	Author authorRequest `json:"author"`
+	Items  []itemRequest `json:"items" binding:"dive"`
+	Note   string        `json:"-" form:"note_text" binding:"omitempty,min=2"`
+}
+
+func bindJSON(t *testing.T, body string) (bool, ValidErrors) {
+	t.Helper()
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodPost, "/posts", strings.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	return BindAndValid(c, &postRequest{})
+}
+
+func TestBindAndValidErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []ValidError
+	}{
+		{
+			name: "malformed json",
+			body: `{"title":`,
+			want: []ValidError{{Key: "", Rule: RuleDecode}},
+		},
+		{
+			name: "type mismatch",
+			body: `{"title":"go","count":"ten"}`,
+			want: []ValidError{{Key: "count", Rule: RuleDecode, Param: "int"}},
+		},
+		{
+			name: "nested type mismatch",
+			body: `{"title":"go","author":{"email":1}}`,
+			want: []ValidError{{Key: "author.email", Rule: RuleDecode, Param: "string"}},
+		},
+		{
+			name: "rule with param",
+			body: `{"title":"golang"}`,
+			want: []ValidError{{Key: "title", Rule: "max", Param: "5"}},
+		},
+		{
+			name: "nested field uses json tag",
+			body: `{"title":"go","author":{"email":"nope"}}`,
+			want: []ValidError{{Key: "author.email", Rule: "email"}},
+		},
+		{
+			name: "slice element keeps index",
+			body: `{"title":"go","items":[{"name":"a"},{"name":""}]}`,
+			want: []ValidError{{Key: "items[1].name", Rule: "required"}},
+		},
+		{
+			name: "several fields",
+			body: `{"items":[{}]}`,
+			want: []ValidError{{Key: "title", Rule: "required"}, {Key: "items[0].name", Rule: "required"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			valid, errs := bindJSON(t, tt.body)
+			if valid != (len(tt.want) == 0) {
+				t.Fatalf("valid = %v, errs = %v", valid, errs)
+			}
+			if len(errs) != len(tt.want) {
+				t.Fatalf("errs = %+v, want %d errors", errs, len(tt.want))
+			}
+			for i, want := range tt.want {
+				got := errs[i]
+				if got.Key != want.Key || got.Rule != want.Rule || got.Param != want.Param {
+					t.Errorf("errs[%d] = %s/%s/%s, want %s/%s/%s", i, got.Key, got.Rule, got.Param, want.Key, want.Rule, want.Param)
+				}
+				if got.Message == "" {
+					t.Errorf("errs[%d] has no message", i)
+				}
+			}
+		})
+	}
+}
+
+// json 标签为 - 时使用 form 标签中的名称
+func TestFieldNameFormTag(t *testing.T) {
+	got := fieldName(reflect.TypeOf(&postRequest{}), "postRequest.Note")
+	if got != "note_text" {
+		t.Errorf("fieldName = %q, want note_text", got)
+	}
+}
+
+func TestToValidErrorResponseFields(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	_, errs := bindJSON(t, `{"title":"golang","items":[{}]}`)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/posts", nil)
+	NewResponse(c).ToValidErrorResponse(errs)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", w.Code)
+	}
+	var body struct {
+		Code    int                      `json:"code"`
+		Key     string                   `json:"key"`
+		Details []string                 `json:"details"`
+		Fields  map[string][]*ValidError `json:"fields"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Code != errcode.InvalidParams.Code() || body.Key != "invalid_params" {
+		t.Errorf("code = %d, key = %q", body.Code, body.Key)
+	}
+	if len(body.Details) != 2 {
+		t.Errorf("details = %q, want 2 messages", body.Details)
+	}
+	if len(body.Fields) != 2 {
+		t.Fatalf("fields = %v, want title and items[0].name", body.Fields)
+	}
+	title := body.Fields["title"]
+	if len(title) != 1 || title[0].Key != "title" || title[0].Rule != "max" || title[0].Param != "5" || title[0].Message == "" {
+		t.Errorf("fields[title] = %+v", title)
+	}
+	if item := body.Fields["items[0].name"]; len(item) != 1 || item[0].Rule != "required" {
+		t.Errorf("fields[items[0].name] = %+v", item)
+	}
+
+	//请求体无法解析的错误归在空字段下
+	_, errs = bindJSON(t, `{"title":`)
+	w = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/posts", nil)
+	NewResponse(c).ToValidErrorResponse(errs)
+	var decode struct {
+		Fields map[string][]*ValidError `json:"fields"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &decode); err != nil {
+		t.Fatal(err)
+	}
+	if errs := decode.Fields[""]; len(errs) != 1 || errs[0].Rule != RuleDecode {
+		t.Errorf("fields[\"\"] = %+v, want one decode error", errs)
+	}
+}
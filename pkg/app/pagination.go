@@ -17,11 +17,12 @@ func GetPage(c *gin.Context) int {
 
 func GetPageSize(c *gin.Context) int {
 	pageSize := convert.StrTo(c.Query("page_size")).MustInt()
+	appSetting := global.AppSetting.Load()
 	if pageSize <= 0 {
-		return global.AppSetting.DefaultPageSize
+		return appSetting.DefaultPageSize
 	}
-	if pageSize > global.AppSetting.MaxPageSize {
-		return global.AppSetting.MaxPageSize
+	if pageSize > appSetting.MaxPageSize {
+		return appSetting.MaxPageSize
 	}
 	return pageSize
 }
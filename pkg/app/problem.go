@@ -0,0 +1,61 @@
+package app
+
+import (
+	"mime"
+	"strings"
+
+	"github.com/ludyyy-lu/goBlogService/global"
+	"github.com/ludyyy-lu/goBlogService/pkg/errcode"
+)
+
+const ProblemContentType = "application/problem+json"
+
+// RFC 7807 定义的错误响应，code 和 key 为扩展字段，与旧格式保持一致
+type Problem struct {
+	Type     string      `json:"type"`
+	Title    string      `json:"title"`
+	Status   int         `json:"status"`
+	Detail   string      `json:"detail,omitempty"`
+	Instance string      `json:"instance,omitempty"`
+	Code     int         `json:"code"`
+	Key      string      `json:"key"`
+	Errors   ValidErrors `json:"errors,omitempty"`
+}
+
+func (r *Response) toProblem(err *errcode.Error, errs ValidErrors) {
+	problem := Problem{
+		Type:     problemType(err),
+		Title:    err.LocaleMsg(r.locale()),
+		Status:   err.StatusCode(),
+		Detail:   strings.Join(err.Details(), "; "),
+		Instance: r.Ctx.Request.URL.Path,
+		Code:     err.Code(),
+		Key:      err.Key(),
+	}
+	problem.Errors = errs
+	r.Ctx.Header("Content-Type", ProblemContentType)
+	r.Ctx.JSON(problem.Status, problem)
+}
+
+// 配置为 problem 或客户端在 Accept 中声明接受 problem+json 时使用 RFC 7807 格式
+func (r *Response) wantsProblem() bool {
+	if appSetting := global.AppSetting.Load(); appSetting != nil && appSetting.ErrorFormat == "problem" {
+		return true
+	}
+	for _, accept := range strings.Split(r.Ctx.GetHeader("Accept"), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(accept))
+		if err == nil && mediaType == ProblemContentType {
+			return true
+		}
+	}
+	return false
+}
+
+// type 指向错误码目录中的对应条目，未配置时使用 about:blank
+func problemType(err *errcode.Error) string {
+	appSetting := global.AppSetting.Load()
+	if appSetting == nil || appSetting.ProblemTypeBaseUrl == "" {
+		return "about:blank"
+	}
+	return appSetting.ProblemTypeBaseUrl + err.Key()
+}
@@ -0,0 +1,48 @@
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ludyyy-lu/goBlogService/pkg/errcode"
+)
+
+func TestProblemInstance(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		name   string
+		target string
+		want   string
+	}{
+		{"path only", "/api/v1/tags/7", "/api/v1/tags/7"},
+		//查询参数中可能带有 token 等敏感信息，不能写入响应
+		{"drops query", "/api/v1/tags/7?token=secret&page=2", "/api/v1/tags/7"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, tt.target, nil)
+			c.Request.Header.Set("Accept", ProblemContentType)
+			NewResponse(c).ToErrorResponse(errcode.NotFound)
+
+			if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, ProblemContentType) {
+				t.Fatalf("Content-Type = %q, want %s", ct, ProblemContentType)
+			}
+			var problem Problem
+			if err := json.Unmarshal(w.Body.Bytes(), &problem); err != nil {
+				t.Fatal(err)
+			}
+			if problem.Instance != tt.want {
+				t.Errorf("instance = %q, want %q", problem.Instance, tt.want)
+			}
+			if strings.Contains(w.Body.String(), "secret") {
+				t.Errorf("body %s leaks the query string", w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package app
+
+import (
+	"context"
+	"reflect"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	val "github.com/go-playground/validator/v10"
+)
+
+// 替换 gin 的 binding.Validator 后，BindAndValid 绑定的对象改为带上请求上下文校验，
+// 使查询数据库的规则能沿用请求的超时和链路；其他绑定仍按 gin 的方式以 context.Background() 校验
+type ContextValidator struct {
+	validate *val.Validate
+	//BindAndValid 正在绑定的对象，绑定过程中跳过校验，绑定完成后再带上请求上下文校验一次
+	binding sync.Map
+}
+
+func NewContextValidator(v *val.Validate) *ContextValidator {
+	return &ContextValidator{validate: v}
+}
+
+func (v *ContextValidator) ValidateStruct(obj any) error {
+	if v.isBinding(obj) {
+		return nil
+	}
+	return v.validateCtx(context.Background(), obj)
+}
+
+func (v *ContextValidator) Engine() any {
+	return v.validate
+}
+
+// 与 gin 的默认校验器一致，只校验结构体
+func (v *ContextValidator) validateCtx(ctx context.Context, obj any) error {
+	if reflect.Indirect(reflect.ValueOf(obj)).Kind() != reflect.Struct {
+		return nil
+	}
+	return v.validate.StructCtx(ctx, obj)
+}
+
+// 绑定请求参数后带上请求上下文校验，规则只执行一次
+func (v *ContextValidator) bindCtx(c *gin.Context, obj any) error {
+	//绑定目标总是指针，以指针区分同时处理的各个请求
+	if reflect.ValueOf(obj).Kind() == reflect.Ptr {
+		v.binding.Store(obj, struct{}{})
+		defer v.binding.Delete(obj)
+	}
+	if err := c.ShouldBind(obj); err != nil {
+		return err
+	}
+	return v.validateCtx(c.Request.Context(), obj)
+}
+
+func (v *ContextValidator) isBinding(obj any) bool {
+	if reflect.ValueOf(obj).Kind() != reflect.Ptr {
+		return false
+	}
+	_, ok := v.binding.Load(obj)
+	return ok
+}
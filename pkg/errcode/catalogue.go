@@ -0,0 +1,66 @@
+package errcode
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/ludyyy-lu/goBlogService/pkg/i18n"
+)
+
+//go:generate go run ../../cmd/errcode-doc -o ../../docs/errcode.md
+
+// 错误码目录中的一项，Messages 以语言为索引
+type Entry struct {
+	Code     int               `json:"code"`
+	Key      string            `json:"key"`
+	Status   int               `json:"status"`
+	Messages map[string]string `json:"messages"`
+}
+
+var locales = []string{i18n.ZH, i18n.EN, i18n.ZHTW}
+
+// 按错误码排序返回所有已注册的错误码
+func Catalogue() []Entry {
+	entries := make([]Entry, 0, len(codes))
+	for _, e := range codes {
+		messages := make(map[string]string, len(locales))
+		for _, locale := range locales {
+			messages[locale] = e.LocaleMsg(locale)
+		}
+		entries = append(entries, Entry{
+			Code:     e.code,
+			Key:      e.key,
+			Status:   e.status,
+			Messages: messages,
+		})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Code < entries[j].Code
+	})
+	return entries
+}
+
+// 以 Markdown 表格输出错误码目录
+func WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("# 错误码\n\n")
+	b.WriteString("| Code | Key | HTTP Status |")
+	for _, locale := range locales {
+		fmt.Fprintf(&b, " %s |", locale)
+	}
+	b.WriteString("\n| --- | --- | --- |")
+	b.WriteString(strings.Repeat(" --- |", len(locales)))
+	b.WriteString("\n")
+	for _, entry := range Catalogue() {
+		fmt.Fprintf(&b, "| %d | `%s` | %d %s |", entry.Code, entry.Key, entry.Status, http.StatusText(entry.Status))
+		for _, locale := range locales {
+			fmt.Fprintf(&b, " %s |", strings.ReplaceAll(entry.Messages[locale], "|", "\\|"))
+		}
+		b.WriteString("\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
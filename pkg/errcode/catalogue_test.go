@@ -0,0 +1,22 @@
+package errcode
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+// docs/errcode.md 由 go generate 生成，不应手工修改
+func TestMarkdownUpToDate(t *testing.T) {
+	want, err := os.ReadFile("../../docs/errcode.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got strings.Builder
+	if err := WriteMarkdown(&got); err != nil {
+		t.Fatal(err)
+	}
+	if got.String() != string(want) {
+		t.Errorf("docs/errcode.md is out of date, run: go generate ./pkg/errcode\n\ngenerated:\n%s", got.String())
+	}
+}
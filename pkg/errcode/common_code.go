@@ -1,13 +1,19 @@
 package errcode
 
+import "net/http"
+
 var (
-	Success                   = NewError(0, "成功")
-	ServerError               = NewError(10000000, "服务器内部错误")
-	InvalidParams             = NewError(10000001, "入参错误")
-	NotFound                  = NewError(10000002, "找不到")
-	UnauthorizedAuthNotExist  = NewError(10000003, "鉴权失败，找不到对应的AppKey和AppSecret")
-	UnauthorizedTokenError    = NewError(10000004, "鉴权失败，Token错误")
-	UnauthorizedTokenTimeout  = NewError(10000005, "鉴权失败，Token超时")
-	UnauthorizedTokenGenerate = NewError(10000006, "鉴权失败，Token生成失败")
-	TooManyRequests           = NewError(10000007, "请求过多")
+	Success                   = NewError(0, http.StatusOK, "success", "成功")
+	ServerError               = NewError(10000000, http.StatusInternalServerError, "server_error", "服务器内部错误")
+	InvalidParams             = NewError(10000001, http.StatusBadRequest, "invalid_params", "入参错误")
+	NotFound                  = NewError(10000002, http.StatusNotFound, "not_found", "找不到")
+	UnauthorizedAuthNotExist  = NewError(10000003, http.StatusUnauthorized, "unauthorized.auth_not_exist", "鉴权失败，找不到对应的AppKey和AppSecret")
+	UnauthorizedTokenError    = NewError(10000004, http.StatusUnauthorized, "unauthorized.token_error", "鉴权失败，Token错误")
+	UnauthorizedTokenTimeout  = NewError(10000005, http.StatusUnauthorized, "unauthorized.token_timeout", "鉴权失败，Token超时")
+	UnauthorizedTokenGenerate = NewError(10000006, http.StatusUnauthorized, "unauthorized.token_generate", "鉴权失败，Token生成失败")
+	TooManyRequests           = NewError(10000007, http.StatusTooManyRequests, "too_many_requests", "请求过多，请 %d 秒后重试")
+	UnauthorizedOIDCFail      = NewError(10000008, http.StatusUnauthorized, "unauthorized.oidc_fail", "鉴权失败，第三方身份认证失败")
+	Conflict                  = NewError(10000009, http.StatusConflict, "conflict", "资源冲突")
+	UnprocessableEntity       = NewError(10000010, http.StatusUnprocessableEntity, "unprocessable_entity", "请求无法处理")
+	Forbidden                 = NewError(10000011, http.StatusForbidden, "forbidden", "没有访问权限")
 )
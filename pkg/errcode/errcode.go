@@ -7,36 +7,97 @@ import (
 
 type Error struct {
 	code    int      //`json:"code"`
+	status  int      //对应的 HTTP 状态码
+	key     string   //`json:"key"`
 	msg     string   //`json:"msg"`
+	args    []any    //信息中占位符的参数，各语言的信息使用同一组参数
 	details []string //`json:"details"`
+	cause   error    //导致该错误的底层错误，只用于日志，不返回给客户端
 }
 
-var codes = map[int]string{}
+var codes = map[int]*Error{}
+var keys = map[string]int{}
 
-func NewError(code int, msg string) *Error {
+// status 为返回的 HTTP 状态码；key 是稳定的机器可读标识，同时作为多语言信息的索引；msg 为默认的简体中文信息
+func NewError(code, status int, key, msg string) *Error {
 	if _, ok := codes[code]; ok {
 		panic(fmt.Sprintf("错误码 %d 已经存在，请更换一个", code))
 	}
-	codes[code] = msg
-	return &Error{code: code, msg: msg}
+	if c, ok := keys[key]; ok {
+		panic(fmt.Sprintf("错误标识 %s 已被错误码 %d 使用，请更换一个", key, c))
+	}
+	if http.StatusText(status) == "" {
+		panic(fmt.Sprintf("错误码 %d 的 HTTP 状态码 %d 无效", code, status))
+	}
+	e := &Error{code: code, status: status, key: key, msg: msg}
+	codes[code] = e
+	keys[key] = code
+	return e
 }
 
 func (e *Error) Error() string {
+	if e.cause != nil {
+		return fmt.Sprintf("错误码：%d,错误信息：%s,原因：%v", e.Code(), e.Msg(), e.cause)
+	}
 	return fmt.Sprintf("错误码：%d,错误信息：%s", e.Code(), e.Msg())
 }
 
+// 返回携带底层错误的副本，便于 errors.Is/As 沿错误链查找
+func (e *Error) WithCause(err error) *Error {
+	newError := *e
+	newError.cause = err
+	return &newError
+}
+
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+// 错误码相同即视为同一种错误，WithDetails 等方法返回的副本也能与原错误匹配
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.code == e.code
+}
+
 func (e *Error) Code() int {
 	return e.code
 }
 
+func (e *Error) Key() string {
+	return e.key
+}
+
 func (e *Error) Msg() string {
-	return e.msg
+	return e.format(e.msg)
 }
 
 func (e *Error) Msgf(args []interface{}) string {
 	return fmt.Sprintf(e.msg, args...)
 }
 
+// 按语言返回错误信息，没有对应翻译时使用默认信息
+func (e *Error) LocaleMsg(locale string) string {
+	if msg, ok := catalogs[locale][e.key]; ok {
+		return e.format(msg)
+	}
+	return e.Msg()
+}
+
+// 返回携带信息参数的副本，输出信息时按 fmt.Sprintf 替换其中的占位符
+func (e *Error) WithArgs(args ...any) *Error {
+	newError := *e
+	newError.args = args
+	return &newError
+}
+
+// 没有参数时原样返回，目录中列出的是带占位符的信息
+func (e *Error) format(msg string) string {
+	if len(e.args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, e.args...)
+}
+
 func (e *Error) Details() []string {
 	return e.details
 }
@@ -53,23 +114,5 @@ func (e *Error) WithDetails(details ...string) *Error {
 }
 
 func (e *Error) StatusCode() int {
-	switch e.Code() {
-	case Success.Code():
-		return http.StatusOK
-	case ServerError.Code():
-		return http.StatusInternalServerError
-	case InvalidParams.Code():
-		return http.StatusBadRequest
-	case UnauthorizedAuthNotExist.Code():
-		fallthrough
-	case UnauthorizedTokenError.Code():
-		fallthrough
-	case UnauthorizedTokenGenerate.Code():
-		fallthrough
-	case UnauthorizedTokenTimeout.Code():
-		return http.StatusUnauthorized
-	case TooManyRequests.Code():
-		return http.StatusTooManyRequests
-	}
-	return http.StatusInternalServerError
+	return e.status
 }
@@ -0,0 +1,145 @@
+package errcode
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/ludyyy-lu/goBlogService/pkg/i18n"
+)
+
+// 注册测试用的错误码，结束后从全局表中移除，避免影响其他用例和 -count
+func newTestError(t *testing.T, code, status int, key, msg string) *Error {
+	t.Helper()
+	e := NewError(code, status, key, msg)
+	t.Cleanup(func() {
+		delete(codes, code)
+		delete(keys, key)
+	})
+	return e
+}
+
+func TestNewErrorPanics(t *testing.T) {
+	newTestError(t, 99990001, http.StatusBadRequest, "test.first", "第一个")
+	tests := []struct {
+		name   string
+		code   int
+		status int
+		key    string
+	}{
+		{"duplicate code", 99990001, http.StatusBadRequest, "test.other"},
+		{"duplicate key", 99990002, http.StatusBadRequest, "test.first"},
+		{"invalid status", 99990003, 999, "test.status"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("NewError(%d, %d, %q) did not panic", tt.code, tt.status, tt.key)
+				}
+			}()
+			NewError(tt.code, tt.status, tt.key, "msg")
+		})
+	}
+}
+
+func TestLocaleMsg(t *testing.T) {
+	untranslated := newTestError(t, 99990010, http.StatusBadRequest, "test.untranslated", "未翻译")
+	tests := []struct {
+		name   string
+		err    *Error
+		locale string
+		want   string
+	}{
+		{"default locale", InvalidParams, i18n.ZH, "入参错误"},
+		{"english", InvalidParams, i18n.EN, "Invalid parameters"},
+		{"traditional chinese", NotFound, i18n.ZHTW, "找不到"},
+		{"unknown locale falls back to default", InvalidParams, "fr", "入参错误"},
+		{"empty locale falls back to default", InvalidParams, "", "入参错误"},
+		{"missing translation falls back to default", untranslated, i18n.EN, "未翻译"},
+		{"copies keep the key", InvalidParams.WithDetails("name").WithCause(errors.New("x")), i18n.EN, "Invalid parameters"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.LocaleMsg(tt.locale); got != tt.want {
+				t.Errorf("LocaleMsg(%q) = %q, want %q", tt.locale, got, tt.want)
+			}
+		})
+	}
+}
+
+// 每个错误码在所有语言中都有翻译，简体中文使用注册时的信息
+func TestCatalogsComplete(t *testing.T) {
+	for locale, catalog := range catalogs {
+		for key := range keys {
+			if _, ok := catalog[key]; !ok {
+				t.Errorf("%s catalog has no message for %q", locale, key)
+			}
+		}
+		for key := range catalog {
+			if _, ok := keys[key]; !ok {
+				t.Errorf("%s catalog has a message for unknown key %q", locale, key)
+			}
+		}
+	}
+}
+
+func TestWithCause(t *testing.T) {
+	cause := errors.New("connection refused")
+	wrapped := fmt.Errorf("get tag 1: %w", cause)
+	err := ErrorGetTagListFail.WithCause(wrapped)
+
+	if !errors.Is(err, cause) {
+		t.Error("errors.Is(err, cause) = false, want the cause to be reachable")
+	}
+	if !errors.Is(err, ErrorGetTagListFail) {
+		t.Error("errors.Is(err, ErrorGetTagListFail) = false")
+	}
+	if errors.Is(err, ErrorCreateTagFail) {
+		t.Error("errors.Is(err, ErrorCreateTagFail) = true, want codes to differ")
+	}
+	if !errors.Is(fmt.Errorf("handler: %w", err), ErrorGetTagListFail) {
+		t.Error("errors.Is through an outer wrap = false")
+	}
+	if got := errors.Unwrap(err); got != wrapped {
+		t.Errorf("Unwrap() = %v, want %v", got, wrapped)
+	}
+	var target *Error
+	if !errors.As(fmt.Errorf("handler: %w", err), &target) || target.Code() != ErrorGetTagListFail.Code() {
+		t.Errorf("errors.As = %v, want the errcode.Error", target)
+	}
+	if ErrorGetTagListFail.Unwrap() != nil {
+		t.Error("WithCause modified the shared error")
+	}
+}
+
+func TestWithArgs(t *testing.T) {
+	err := TooManyRequests.WithArgs(30)
+	tests := []struct {
+		name   string
+		err    *Error
+		locale string
+		want   string
+	}{
+		{"default locale", err, i18n.ZH, "请求过多，请 30 秒后重试"},
+		{"english", err, i18n.EN, "Too many requests, retry in 30 seconds"},
+		{"traditional chinese", err, i18n.ZHTW, "請求過多，請 30 秒後重試"},
+		{"unknown locale falls back to default", err, "fr", "请求过多，请 30 秒后重试"},
+		{"copies keep the args", err.WithDetails("GET /auth"), i18n.EN, "Too many requests, retry in 30 seconds"},
+		{"without args the template is kept", TooManyRequests, i18n.EN, "Too many requests, retry in %d seconds"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.LocaleMsg(tt.locale); got != tt.want {
+				t.Errorf("LocaleMsg(%q) = %q, want %q", tt.locale, got, tt.want)
+			}
+		})
+	}
+	if got, want := err.Msg(), "请求过多，请 30 秒后重试"; got != want {
+		t.Errorf("Msg() = %q, want %q", got, want)
+	}
+	if got, want := TooManyRequests.Msgf([]any{5}), "请求过多，请 5 秒后重试"; got != want {
+		t.Errorf("Msgf() = %q, want %q", got, want)
+	}
+}
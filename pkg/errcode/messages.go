@@ -0,0 +1,55 @@
+package errcode
+
+import "github.com/ludyyy-lu/goBlogService/pkg/i18n"
+
+// 各语言的错误信息，以错误码的 key 为索引；简体中文使用注册时的默认信息
+var catalogs = map[string]map[string]string{
+	i18n.EN: {
+		"success":                     "Success",
+		"server_error":                "Internal server error",
+		"invalid_params":              "Invalid parameters",
+		"not_found":                   "Not found",
+		"unauthorized.auth_not_exist": "Authentication failed, no matching AppKey and AppSecret",
+		"unauthorized.token_error":    "Authentication failed, invalid token",
+		"unauthorized.token_timeout":  "Authentication failed, token expired",
+		"unauthorized.token_generate": "Authentication failed, unable to generate token",
+		"too_many_requests":           "Too many requests, retry in %d seconds",
+		"unauthorized.oidc_fail":      "Authentication failed, identity provider sign-in failed",
+		"conflict":                    "Resource conflict",
+		"unprocessable_entity":        "Unprocessable request",
+		"forbidden":                   "Access denied",
+		"tag.get_list_fail":           "Failed to get tag list",
+		"tag.create_fail":             "Failed to create tag",
+		"tag.update_fail":             "Failed to update tag",
+		"tag.delete_fail":             "Failed to delete tag",
+		"tag.count_fail":              "Failed to count tags",
+		"tag.not_exist":               "Tag does not exist",
+		"tag.exists":                  "Tag name already exists",
+		"upload.file_fail":            "Failed to upload file",
+		"upload.file_invalid":         "Uploaded file type or size is not allowed",
+	},
+	i18n.ZHTW: {
+		"success":                     "成功",
+		"server_error":                "伺服器內部錯誤",
+		"invalid_params":              "參數錯誤",
+		"not_found":                   "找不到",
+		"unauthorized.auth_not_exist": "驗證失敗，找不到對應的AppKey和AppSecret",
+		"unauthorized.token_error":    "驗證失敗，Token錯誤",
+		"unauthorized.token_timeout":  "驗證失敗，Token逾時",
+		"unauthorized.token_generate": "驗證失敗，Token產生失敗",
+		"too_many_requests":           "請求過多，請 %d 秒後重試",
+		"unauthorized.oidc_fail":      "驗證失敗，第三方身分驗證失敗",
+		"conflict":                    "資源衝突",
+		"unprocessable_entity":        "請求無法處理",
+		"forbidden":                   "沒有存取權限",
+		"tag.get_list_fail":           "取得標籤列表失敗",
+		"tag.create_fail":             "建立標籤失敗",
+		"tag.update_fail":             "更新標籤失敗",
+		"tag.delete_fail":             "刪除標籤失敗",
+		"tag.count_fail":              "統計標籤失敗",
+		"tag.not_exist":               "標籤不存在",
+		"tag.exists":                  "標籤名稱已存在",
+		"upload.file_fail":            "上傳檔案失敗",
+		"upload.file_invalid":         "上傳檔案的類型或大小不符合要求",
+	},
+}
@@ -1,10 +1,15 @@
 package errcode
 
+import "net/http"
+
 var (
-	ErrorGetTagListFail = NewError(20010001, "获取标签列表失败")
-	ErrorCreateTagFail  = NewError(20010002, "创建标签失败")
-	ErrorUpdateTagFail  = NewError(20010003, "更新标签失败")
-	ErrorDeleteTagFail  = NewError(20010004, "删除标签失败")
-	ErrorCountTagFail   = NewError(20010005, "统计标签失败")
-	ErrorUploadFileFail = NewError(20030001, "上传文件失败")
+	ErrorGetTagListFail = NewError(20010001, http.StatusInternalServerError, "tag.get_list_fail", "获取标签列表失败")
+	ErrorCreateTagFail  = NewError(20010002, http.StatusInternalServerError, "tag.create_fail", "创建标签失败")
+	ErrorUpdateTagFail  = NewError(20010003, http.StatusInternalServerError, "tag.update_fail", "更新标签失败")
+	ErrorDeleteTagFail  = NewError(20010004, http.StatusInternalServerError, "tag.delete_fail", "删除标签失败")
+	ErrorCountTagFail   = NewError(20010005, http.StatusInternalServerError, "tag.count_fail", "统计标签失败")
+	ErrorTagNotExist    = NewError(20010006, http.StatusNotFound, "tag.not_exist", "标签不存在")
+	ErrorTagExists      = NewError(20010007, http.StatusConflict, "tag.exists", "标签名称已存在")
+	ErrorUploadFileFail = NewError(20030001, http.StatusInternalServerError, "upload.file_fail", "上传文件失败")
+	ErrorUploadFileType = NewError(20030002, http.StatusUnprocessableEntity, "upload.file_invalid", "上传文件的类型或大小不符合要求")
 )
@@ -0,0 +1,64 @@
+package health
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"path/filepath"
+)
+
+func DBPing(db *sql.DB) CheckFunc {
+	return func(ctx context.Context) error {
+		return db.PingContext(ctx)
+	}
+}
+
+// 通过创建并删除临时文件确认目录可写。每次检查时读取目录，配置变更后检查新目录；
+// 目录由上传服务按需创建，不存在时检查最近的已存在上级目录，能在其中创建即视为可写
+func DirWritable(dir func() string) CheckFunc {
+	return func(ctx context.Context) error {
+		path, err := existingDir(dir())
+		if err != nil {
+			return err
+		}
+		f, err := os.CreateTemp(path, ".readyz-*")
+		if err != nil {
+			return err
+		}
+		name := f.Name()
+		return errors.Join(f.Close(), os.Remove(name))
+	}
+}
+
+func existingDir(dir string) (string, error) {
+	for {
+		info, err := os.Stat(dir)
+		if err == nil {
+			if !info.IsDir() {
+				return "", fmt.Errorf("%s is not a directory", dir)
+			}
+			return dir, nil
+		}
+		parent := filepath.Dir(dir)
+		if !errors.Is(err, fs.ErrNotExist) || parent == dir {
+			return "", err
+		}
+		dir = parent
+	}
+}
+
+// 只检查能否建立 TCP 连接，不做 SMTP 握手和认证
+func TCPDial(address string) CheckFunc {
+	return func(ctx context.Context) error {
+		var d net.Dialer
+		conn, err := d.DialContext(ctx, "tcp", address)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	}
+}
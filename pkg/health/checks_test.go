@@ -0,0 +1,55 @@
+package health
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDirWritable(t *testing.T) {
+	root := t.TempDir()
+	file := filepath.Join(root, "file")
+	if err := os.WriteFile(file, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		dir     string
+		wantErr bool
+	}{
+		{"existing dir", root, false},
+		{"missing dir under writable parent", filepath.Join(root, "storage", "uploads"), false},
+		{"path is a file", file, true},
+		{"parent is a file", filepath.Join(file, "uploads"), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := DirWritable(func() string { return tt.dir })(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+	entries, _ := os.ReadDir(root)
+	if len(entries) != 1 {
+		t.Errorf("check left %d entries in %s, want only the fixture file", len(entries), root)
+	}
+}
+
+// 目录在每次检查时读取，配置重载后检查新目录
+func TestDirWritableReadsDirEachCheck(t *testing.T) {
+	dir := t.TempDir()
+	check := DirWritable(func() string { return dir })
+	if err := check(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(dir, "file")
+	if err := os.WriteFile(file, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	dir = file
+	if err := check(context.Background()); err == nil {
+		t.Error("err = nil after switching to a file path, want error")
+	}
+}
@@ -0,0 +1,103 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	StatusOK           = "ok"
+	StatusFail         = "fail"
+	StatusShuttingDown = "shutting_down"
+)
+
+type CheckFunc func(ctx context.Context) error
+
+type CheckResult struct {
+	Status     string  `json:"status"`
+	DurationMs float64 `json:"duration_ms"`
+	Error      string  `json:"error,omitempty"`
+}
+
+type Report struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks,omitempty"`
+}
+
+type check struct {
+	name string
+	fn   CheckFunc
+}
+
+// 汇总各项依赖检查，供就绪探针使用；关闭服务时先标记为未就绪
+type Checker struct {
+	timeout  time.Duration
+	checks   []check
+	draining atomic.Bool
+}
+
+func NewChecker(timeout time.Duration) *Checker {
+	if timeout <= 0 {
+		timeout = 2 * time.Second
+	}
+	return &Checker{timeout: timeout}
+}
+
+func (c *Checker) Register(name string, fn CheckFunc) *Checker {
+	c.checks = append(c.checks, check{name: name, fn: fn})
+	return c
+}
+
+// 标记服务正在关闭，此后就绪检查一律返回失败
+func (c *Checker) SetDraining() {
+	c.draining.Store(true)
+}
+
+func (c *Checker) Draining() bool {
+	return c.draining.Load()
+}
+
+// 并发执行所有检查，任意一项失败则整体失败
+func (c *Checker) Check(ctx context.Context) Report {
+	if c.Draining() {
+		return Report{Status: StatusShuttingDown}
+	}
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	results := make([]CheckResult, len(c.checks))
+	var wg sync.WaitGroup
+	for i, ck := range c.checks {
+		wg.Add(1)
+		go func(i int, ck check) {
+			defer wg.Done()
+			results[i] = run(ctx, ck.fn)
+		}(i, ck)
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusOK, Checks: make(map[string]CheckResult, len(c.checks))}
+	for i, ck := range c.checks {
+		if results[i].Status != StatusOK {
+			report.Status = StatusFail
+		}
+		report.Checks[ck.name] = results[i]
+	}
+	return report
+}
+
+func run(ctx context.Context, fn CheckFunc) CheckResult {
+	begin := time.Now()
+	err := fn(ctx)
+	result := CheckResult{
+		Status:     StatusOK,
+		DurationMs: float64(time.Since(begin).Microseconds()) / 1000,
+	}
+	if err != nil {
+		result.Status = StatusFail
+		result.Error = err.Error()
+	}
+	return result
+}
@@ -0,0 +1,87 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCheck(t *testing.T) {
+	ok := func(context.Context) error { return nil }
+	fail := func(context.Context) error { return errors.New("connection refused") }
+	tests := []struct {
+		name   string
+		checks map[string]CheckFunc
+		want   string
+		failed []string
+	}{
+		{"no checks", nil, StatusOK, nil},
+		{"all ok", map[string]CheckFunc{"database": ok, "upload_dir": ok}, StatusOK, nil},
+		{"one failing", map[string]CheckFunc{"database": fail, "upload_dir": ok}, StatusFail, []string{"database"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewChecker(time.Second)
+			for name, fn := range tt.checks {
+				c.Register(name, fn)
+			}
+			report := c.Check(context.Background())
+			if report.Status != tt.want {
+				t.Errorf("Status = %q, want %q", report.Status, tt.want)
+			}
+			if len(report.Checks) != len(tt.checks) {
+				t.Errorf("len(Checks) = %d, want %d", len(report.Checks), len(tt.checks))
+			}
+			for _, name := range tt.failed {
+				if r := report.Checks[name]; r.Status != StatusFail || r.Error != "connection refused" {
+					t.Errorf("Checks[%q] = %+v, want failure with error", name, r)
+				}
+			}
+		})
+	}
+}
+
+// 检查并发执行，各自记录耗时，超时后以超时错误结束
+func TestCheckTiming(t *testing.T) {
+	c := NewChecker(50*time.Millisecond).
+		Register("slow", func(ctx context.Context) error {
+			time.Sleep(20 * time.Millisecond)
+			return nil
+		}).
+		Register("hung", func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+	begin := time.Now()
+	report := c.Check(context.Background())
+	if elapsed := time.Since(begin); elapsed > 200*time.Millisecond {
+		t.Errorf("Check took %v, want checks to run concurrently within the timeout", elapsed)
+	}
+	if r := report.Checks["slow"]; r.Status != StatusOK || r.DurationMs < 20 {
+		t.Errorf("slow = %+v, want ok with duration >= 20ms", r)
+	}
+	if r := report.Checks["hung"]; r.Status != StatusFail || r.DurationMs < 50 {
+		t.Errorf("hung = %+v, want failure after the 50ms timeout", r)
+	}
+}
+
+func TestCheckDraining(t *testing.T) {
+	var ran bool
+	c := NewChecker(time.Second).Register("database", func(context.Context) error {
+		ran = true
+		return nil
+	})
+	if report := c.Check(context.Background()); report.Status != StatusOK {
+		t.Fatalf("Status = %q before draining, want ok", report.Status)
+	}
+	ran = false
+	c.SetDraining()
+	report := c.Check(context.Background())
+	if report.Status != StatusShuttingDown {
+		t.Errorf("Status = %q, want %q", report.Status, StatusShuttingDown)
+	}
+	if ran {
+		t.Error("checks ran while draining")
+	}
+}
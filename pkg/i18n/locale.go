@@ -0,0 +1,96 @@
+package i18n
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// 服务支持的语言，命名与 Accept-Language 中的语言标签一致
+const (
+	ZH   = "zh"
+	EN   = "en"
+	ZHTW = "zh-TW"
+)
+
+const Default = ZH
+
+// 优先使用 locale 请求头，其次按 Accept-Language 的权重选择第一个支持的语言，都不支持时使用默认语言。
+// * 只表示接受其他任意语言，不论其权重高低，明确列出的支持语言都优先于它
+func Negotiate(locale, acceptLanguage string) string {
+	if l, ok := Match(locale); ok {
+		return l
+	}
+	for _, tag := range parseAcceptLanguage(acceptLanguage) {
+		if l, ok := Match(tag); ok {
+			return l
+		}
+	}
+	return Default
+}
+
+// 协商请求的语言，locale 请求头优先于 locale 查询参数，二者都不支持时按 Accept-Language 选择
+func FromRequest(r *http.Request) string {
+	locale := r.Header.Get("locale")
+	if _, ok := Match(locale); !ok {
+		locale = r.URL.Query().Get("locale")
+	}
+	return Negotiate(locale, r.Header.Get("Accept-Language"))
+}
+
+// 将语言标签归一为支持的语言，地区或文字不同的写法回退到同一语言，繁体中文的各种写法都映射到 zh-TW
+func Match(tag string) (string, bool) {
+	tag = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(tag), "_", "-"))
+	switch {
+	case tag == "":
+		return "", false
+	case tag == "zh-tw" || tag == "zh-hk" || tag == "zh-mo" || strings.HasPrefix(tag, "zh-hant"):
+		return ZHTW, true
+	case tag == "zh" || strings.HasPrefix(tag, "zh-"):
+		return ZH, true
+	case tag == "en" || strings.HasPrefix(tag, "en-"):
+		return EN, true
+	}
+	return "", false
+}
+
+type weightedTag struct {
+	tag string
+	q   float64
+}
+
+// 按 q 值从高到低返回语言标签，q 相同时保持原有顺序，q=0 表示不接受
+func parseAcceptLanguage(header string) []string {
+	var tags []weightedTag
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+		q := 1.0
+		for _, param := range strings.Split(params, ";") {
+			k, v, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if !ok || strings.TrimSpace(k) != "q" {
+				continue
+			}
+			f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+			if err != nil || f < 0 || f > 1 {
+				f = 0
+			}
+			q = f
+		}
+		if q > 0 {
+			tags = append(tags, weightedTag{tag: tag, q: q})
+		}
+	}
+	sort.SliceStable(tags, func(i, j int) bool {
+		return tags[i].q > tags[j].q
+	})
+	result := make([]string, len(tags))
+	for i, t := range tags {
+		result[i] = t.tag
+	}
+	return result
+}
@@ -0,0 +1,61 @@
+package i18n
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		name           string
+		locale         string
+		acceptLanguage string
+		want           string
+	}{
+		{"nothing given", "", "", Default},
+		{"locale header wins", "en", "zh-TW", EN},
+		{"unsupported locale header falls through", "fr", "en", EN},
+		{"highest q wins", "", "zh;q=0.5, en;q=0.9", EN},
+		{"missing q means 1", "", "en;q=0.9, zh-TW", ZHTW},
+		{"equal q keeps header order", "", "en;q=0.8, zh;q=0.8", EN},
+		{"skips unsupported", "", "fr, de;q=0.9, en;q=0.1", EN},
+		{"region falls back to language", "", "en-GB", EN},
+		{"zh-TW", "", "zh-TW", ZHTW},
+		{"zh-HK maps to traditional", "", "zh-HK", ZHTW},
+		{"zh-Hant-TW maps to traditional", "", "zh-Hant-TW", ZHTW},
+		{"zh-CN falls back to zh", "", "zh-CN", ZH},
+		{"underscore and case", "zh_tw", "", ZHTW},
+		{"q=0 is not acceptable", "", "en;q=0, zh-TW;q=0.1", ZHTW},
+		{"wildcard ranks after listed tags", "", "fr, *;q=0.9, en;q=0.1", EN},
+		{"wildcard alone", "", "*", Default},
+		{"malformed q is ignored", "", "en;q=abc, zh-TW;q=0.2", ZHTW},
+		{"out of range q is ignored", "", "en;q=1.5, zh-TW;q=0.2", ZHTW},
+		{"empty entries", "", " , ;q=0.5,, en", EN},
+		{"garbage", "", ";;;===,,,", Default},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Negotiate(tt.locale, tt.acceptLanguage); got != tt.want {
+				t.Errorf("Negotiate(%q, %q) = %q, want %q", tt.locale, tt.acceptLanguage, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseAcceptLanguage(t *testing.T) {
+	tests := []struct {
+		header string
+		want   []string
+	}{
+		{"", []string{}},
+		{"da, en-GB;q=0.8, en;q=0.7", []string{"da", "en-GB", "en"}},
+		{"en;q=0.7, da, en-GB;q=0.8", []string{"da", "en-GB", "en"}},
+		{"en ; q = 0.5 , fr", []string{"fr", "en"}},
+		{"en;q=0, fr;q=-1", []string{}},
+	}
+	for _, tt := range tests {
+		if got := parseAcceptLanguage(tt.header); !slices.Equal(got, tt.want) {
+			t.Errorf("parseAcceptLanguage(%q) = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
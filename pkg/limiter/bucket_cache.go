@@ -0,0 +1,80 @@
+package limiter
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// 按需创建令牌桶的缓存，通过 LRU 和空闲过期淘汰保证内存占用有上限
+type bucketCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	ttl        time.Duration
+	ll         *list.List
+	items      map[string]*list.Element
+}
+
+type cacheEntry struct {
+	key      string
+	bucket   *localBucket
+	lastUsed time.Time
+}
+
+func newBucketCache(maxEntries int, ttl time.Duration) *bucketCache {
+	return &bucketCache{
+		maxEntries: maxEntries,
+		ttl:        ttl,
+		ll:         list.New(),
+		items:      make(map[string]*list.Element),
+	}
+}
+
+// 获取 key 对应的桶，不存在或已过期时调用 create 创建新的桶
+func (c *bucketCache) Get(key string, create func() *localBucket) *localBucket {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if e, ok := c.items[key]; ok {
+		entry := e.Value.(*cacheEntry)
+		if c.ttl <= 0 || now.Sub(entry.lastUsed) < c.ttl {
+			entry.lastUsed = now
+			c.ll.MoveToFront(e)
+			return entry.bucket
+		}
+		c.removeElement(e)
+	}
+
+	c.evictExpired(now)
+	entry := &cacheEntry{key: key, bucket: create(), lastUsed: now}
+	c.items[key] = c.ll.PushFront(entry)
+	if c.maxEntries > 0 && c.ll.Len() > c.maxEntries {
+		c.removeElement(c.ll.Back())
+	}
+	return entry.bucket
+}
+
+func (c *bucketCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ll.Len()
+}
+
+// 从最久未使用的一端开始清理过期的桶
+func (c *bucketCache) evictExpired(now time.Time) {
+	if c.ttl <= 0 {
+		return
+	}
+	for e := c.ll.Back(); e != nil; e = c.ll.Back() {
+		if now.Sub(e.Value.(*cacheEntry).lastUsed) < c.ttl {
+			return
+		}
+		c.removeElement(e)
+	}
+}
+
+func (c *bucketCache) removeElement(e *list.Element) {
+	c.ll.Remove(e)
+	delete(c.items, e.Value.(*cacheEntry).key)
+}
@@ -0,0 +1,57 @@
+package limiter
+
+import (
+	"testing"
+	"time"
+)
+
+func newTestBucket() *localBucket {
+	return newLocalBucket(LimiterBucketRule{FillInterval: time.Second, Capacity: 1, Quantum: 1})
+}
+
+func TestBucketCacheLRU(t *testing.T) {
+	cache := newBucketCache(2, 0)
+	a := cache.Get("a", newTestBucket)
+	cache.Get("b", newTestBucket)
+	// 访问 a 之后，b 成为最久未使用的桶
+	if got := cache.Get("a", newTestBucket); got != a {
+		t.Fatal("cached bucket a was recreated")
+	}
+	cache.Get("c", newTestBucket)
+
+	if n := cache.Len(); n != 2 {
+		t.Fatalf("Len() = %d, want 2", n)
+	}
+	if got := cache.Get("a", newTestBucket); got != a {
+		t.Error("recently used bucket a was evicted")
+	}
+	if _, ok := cache.items["b"]; ok {
+		t.Error("least recently used bucket b was not evicted")
+	}
+}
+
+func TestBucketCacheTTL(t *testing.T) {
+	cache := newBucketCache(10, 20*time.Millisecond)
+	a := cache.Get("a", newTestBucket)
+	cache.Get("b", newTestBucket)
+	time.Sleep(30 * time.Millisecond)
+
+	if got := cache.Get("a", newTestBucket); got == a {
+		t.Error("idle bucket a was reused after its TTL")
+	}
+	// 创建新桶时会清理其余过期的桶
+	if n := cache.Len(); n != 1 {
+		t.Errorf("Len() = %d, want expired bucket b removed", n)
+	}
+}
+
+func TestBucketCacheTTLRefreshedOnUse(t *testing.T) {
+	cache := newBucketCache(10, 50*time.Millisecond)
+	a := cache.Get("a", newTestBucket)
+	for i := 0; i < 3; i++ {
+		time.Sleep(20 * time.Millisecond)
+		if got := cache.Get("a", newTestBucket); got != a {
+			t.Fatalf("bucket in use was evicted after %d accesses", i)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package limiter
+
+import (
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ludyyy-lu/goBlogService/global"
+	"github.com/ludyyy-lu/goBlogService/pkg/app"
+	"github.com/ludyyy-lu/goBlogService/pkg/setting"
+	"github.com/ludyyy-lu/goBlogService/pkg/utils"
+)
+
+func newTestContext(method, target, remoteAddr string) *gin.Context {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(method, target, nil)
+	c.Request.RemoteAddr = remoteAddr
+	return c
+}
+
+var authRule = LimiterBucketRule{Key: "/auth", FillInterval: time.Hour, Capacity: 1, Quantum: 1}
+
+func TestClientLimiterSeparatesClients(t *testing.T) {
+	l := NewIPLimiter(ClientLimiterOptions{}).AddBuckets(authRule)
+
+	take := func(remoteAddr string) int64 {
+		key := l.Key(newTestContext("GET", "/auth", remoteAddr))
+		bucket, ok := l.GetBucket(key)
+		if !ok {
+			t.Fatalf("no bucket for %s", key)
+		}
+		return bucket.TakeAvailable(1)
+	}
+	if take("10.0.0.1:1234") != 1 {
+		t.Fatal("first request from 10.0.0.1 was rejected")
+	}
+	if take("10.0.0.1:5678") != 0 {
+		t.Error("second request from 10.0.0.1 was allowed")
+	}
+	if take("10.0.0.2:1234") != 1 {
+		t.Error("request from 10.0.0.2 shared the bucket of 10.0.0.1")
+	}
+}
+
+func TestClientLimiterCombinedKey(t *testing.T) {
+	tenant := func(c *gin.Context) string { return c.GetHeader("X-Tenant") }
+	l := NewClientLimiter(ClientLimiterOptions{}, ClientIP, tenant)
+
+	c := newTestContext("GET", "/auth", "10.0.0.1:1234")
+	c.Request.Header.Set("X-Tenant", "a")
+	keyA := l.Key(c)
+	c.Request.Header.Set("X-Tenant", "b")
+	keyB := l.Key(c)
+	if keyA == keyB {
+		t.Errorf("keys for different tenants are equal: %q", keyA)
+	}
+	if want := "GET /auth|10.0.0.1|a"; keyA != want {
+		t.Errorf("Key() = %q, want %q", keyA, want)
+	}
+}
+
+func TestClientLimiterBoundedBuckets(t *testing.T) {
+	l := NewIPLimiter(ClientLimiterOptions{MaxBuckets: 3}).AddBuckets(authRule).(ClientLimiter)
+	for i := 0; i < 10; i++ {
+		key := l.Key(newTestContext("GET", "/auth", "10.0.0."+strconv.Itoa(i)+":1"))
+		if _, ok := l.GetBucket(key); !ok {
+			t.Fatalf("no bucket for %s", key)
+		}
+	}
+	if n := l.buckets.Len(); n != 3 {
+		t.Errorf("buckets = %d, want at most 3", n)
+	}
+}
+
+func TestAppKey(t *testing.T) {
+	global.JWTSetting = &setting.JWTSettingS{Secret: "secret", Issuer: "blog-service", Expire: time.Hour}
+	token, err := app.GenerateToken("alice", "s3cret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name       string
+		target     string
+		header     string
+		remoteAddr string
+		want       string
+	}{
+		{"token in query", "/auth?token=" + token, "", "10.0.0.1:1234", utils.EncodeMD5("alice")},
+		{"token in header", "/auth", token, "10.0.0.1:1234", utils.EncodeMD5("alice")},
+		{"missing token", "/auth", "", "10.0.0.1:1234", "ip:10.0.0.1"},
+		{"invalid token", "/auth?token=bogus", "", "10.0.0.2:1234", "ip:10.0.0.2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestContext("GET", tt.target, tt.remoteAddr)
+			if tt.header != "" {
+				c.Request.Header.Set("token", tt.header)
+			}
+			if got := AppKey(c); got != tt.want {
+				t.Errorf("AppKey() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// 匿名请求按 IP 各自拥有令牌桶，不会耗尽其他客户端的配额
+func TestAppKeyLimiterSeparatesAnonymousClients(t *testing.T) {
+	l := NewAppKeyLimiter(ClientLimiterOptions{}).AddBuckets(authRule)
+	take := func(remoteAddr string) int64 {
+		bucket, ok := l.GetBucket(l.Key(newTestContext("GET", "/auth", remoteAddr)))
+		if !ok {
+			t.Fatal("no bucket for /auth")
+		}
+		return bucket.TakeAvailable(1)
+	}
+	if take("10.0.0.1:1234") != 1 || take("10.0.0.1:1234") != 0 {
+		t.Fatal("anonymous client 10.0.0.1 was not limited")
+	}
+	if take("10.0.0.2:1234") != 1 {
+		t.Error("anonymous client 10.0.0.2 shared the bucket of 10.0.0.1")
+	}
+}
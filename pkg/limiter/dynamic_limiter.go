@@ -0,0 +1,39 @@
+package limiter
+
+import (
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 可在运行时整体替换的限流器，用于配置热加载；替换后原有令牌桶的状态会丢失
+type DynamicLimiter struct {
+	current atomic.Pointer[LimiterIface]
+}
+
+func NewDynamicLimiter(l LimiterIface) *DynamicLimiter {
+	d := &DynamicLimiter{}
+	d.Store(l)
+	return d
+}
+
+func (d *DynamicLimiter) Store(l LimiterIface) {
+	d.current.Store(&l)
+}
+
+func (d *DynamicLimiter) Load() LimiterIface {
+	return *d.current.Load()
+}
+
+func (d *DynamicLimiter) Key(c *gin.Context) string {
+	return d.Load().Key(c)
+}
+
+func (d *DynamicLimiter) GetBucket(key string) (Bucket, bool) {
+	return d.Load().GetBucket(key)
+}
+
+func (d *DynamicLimiter) AddBuckets(rules ...LimiterBucketRule) LimiterIface {
+	d.Load().AddBuckets(rules...)
+	return d
+}
@@ -1,6 +1,8 @@
 package limiter
 
 import (
+	"path"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -9,17 +11,179 @@ import (
 
 type LimiterIface interface {
 	Key(c *gin.Context) string
-	GetBucket(key string) (*ratelimit.Bucket, bool)
+	GetBucket(key string) (Bucket, bool)
 	AddBuckets(rules ...LimiterBucketRule) LimiterIface
 }
 
+// 令牌桶的抽象，*ratelimit.Bucket 与基于共享存储的桶都实现了该接口
+type Bucket interface {
+	TakeAvailable(count int64) int64
+	Available() int64
+	Capacity() int64
+	// 每秒补充的令牌数
+	Rate() float64
+}
+
+// 能直接给出重试和恢复时间的令牌桶，如基于共享存储的桶
+type TimedBucket interface {
+	Bucket
+	// 距离下次放行和恢复满额的时间，基于共享存储的桶使用最近一次访问存储的结果，
+	// 无法给出时返回 false
+	Timing() (retryAfter, resetAfter time.Duration, ok bool)
+}
+
 type Limiter struct {
-	limiterBuckets map[string]*ratelimit.Bucket
+	rules   *ruleSet
+	buckets *bucketCache
 }
 
+// Key 为 gin 的路由模式（如 /api/v1/tags/:id），支持通配符：
+// 以 * 结尾时按前缀匹配，其余位置的 * 只匹配单个路径段，单独的 * 匹配所有路由。
+// Method 为空或 * 时匹配所有请求方法。
 type LimiterBucketRule struct {
+	Method       string
 	Key          string
 	FillInterval time.Duration
 	Capacity     int64
 	Quantum      int64
 }
+
+func newLimiter(maxBuckets int, bucketTTL time.Duration) *Limiter {
+	if maxBuckets <= 0 {
+		maxBuckets = defaultMaxBuckets
+	}
+	if bucketTTL <= 0 {
+		bucketTTL = defaultBucketTTL
+	}
+	return &Limiter{
+		rules:   newRuleSet(),
+		buckets: newBucketCache(maxBuckets, bucketTTL),
+	}
+}
+
+// 根据 "METHOD route" 形式的 route 匹配规则
+func (l *Limiter) matchRule(route string) (LimiterBucketRule, bool) {
+	method, pattern, _ := strings.Cut(route, " ")
+	return l.rules.match(method, pattern)
+}
+
+// 按 key 取出或创建本地令牌桶
+func (l *Limiter) localBucket(key string, rule LimiterBucketRule) *localBucket {
+	return l.buckets.Get(key, func() *localBucket {
+		return newLocalBucket(rule)
+	})
+}
+
+// 进程内的令牌桶。juju 每隔 FillInterval 一次性补充 Quantum 个令牌，补充时刻从创建时开始计算，
+// 这里记录创建时间，以便推算距离下次补充的时间
+type localBucket struct {
+	*ratelimit.Bucket
+	start        time.Time
+	fillInterval time.Duration
+	quantum      int64
+}
+
+func newLocalBucket(rule LimiterBucketRule) *localBucket {
+	// 在 juju 记录起始时间之前取时间，推算出的补充时刻只会略早于实际，向上取整到秒后不受影响
+	start := time.Now()
+	return &localBucket{
+		Bucket:       ratelimit.NewBucketWithQuantum(rule.FillInterval, rule.Capacity, rule.Quantum),
+		start:        start,
+		fillInterval: rule.FillInterval,
+		quantum:      max(rule.Quantum, 1),
+	}
+}
+
+// 令牌用完时需要等到下一次补充；恢复满额需要补足缺少的令牌所需的补充次数
+func (b *localBucket) Timing() (retryAfter, resetAfter time.Duration, ok bool) {
+	if b.fillInterval <= 0 {
+		return 0, 0, false
+	}
+	next := b.fillInterval - time.Since(b.start)%b.fillInterval
+	available := b.Available()
+	if available < 1 {
+		retryAfter = next
+	}
+	if missing := b.Capacity() - available; missing > 0 {
+		ticks := (missing + b.quantum - 1) / b.quantum
+		resetAfter = next + time.Duration(ticks-1)*b.fillInterval
+	}
+	return retryAfter, resetAfter, true
+}
+
+func (l *Limiter) getBucket(key, route string) (Bucket, bool) {
+	rule, ok := l.matchRule(route)
+	if !ok {
+		return nil, false
+	}
+	return l.localBucket(key, rule), true
+}
+
+// 路由的限流键，优先使用路由模式，未匹配到路由时使用请求路径
+func routeKey(c *gin.Context) string {
+	route := c.FullPath()
+	if route == "" {
+		route = c.Request.URL.Path
+	}
+	return c.Request.Method + " " + route
+}
+
+// 精确规则优先，其次按添加顺序匹配通配规则
+type ruleSet struct {
+	exact     map[string]LimiterBucketRule
+	wildcards []LimiterBucketRule
+}
+
+func newRuleSet() *ruleSet {
+	return &ruleSet{exact: make(map[string]LimiterBucketRule)}
+}
+
+func (s *ruleSet) add(rule LimiterBucketRule) {
+	rule.Method = strings.ToUpper(rule.Method)
+	if rule.Method == "" {
+		rule.Method = "*"
+	}
+	if rule.Capacity <= 0 || rule.FillInterval <= 0 {
+		return
+	}
+	if rule.Quantum <= 0 {
+		rule.Quantum = 1
+	}
+	if strings.Contains(rule.Key, "*") {
+		s.wildcards = append(s.wildcards, rule)
+		return
+	}
+	id := rule.Method + " " + rule.Key
+	if _, ok := s.exact[id]; !ok {
+		s.exact[id] = rule
+	}
+}
+
+func (s *ruleSet) match(method, route string) (LimiterBucketRule, bool) {
+	if rule, ok := s.exact[method+" "+route]; ok {
+		return rule, true
+	}
+	if rule, ok := s.exact["* "+route]; ok {
+		return rule, true
+	}
+	for _, rule := range s.wildcards {
+		if rule.Method != "*" && rule.Method != method {
+			continue
+		}
+		if matchPattern(rule.Key, route) {
+			return rule, true
+		}
+	}
+	return LimiterBucketRule{}, false
+}
+
+func matchPattern(pattern, route string) bool {
+	if pattern == "*" {
+		return true
+	}
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok && !strings.Contains(prefix, "*") {
+		return strings.HasPrefix(route, prefix)
+	}
+	matched, _ := path.Match(pattern, route)
+	return matched
+}
@@ -0,0 +1,159 @@
+package limiter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestMatchPattern(t *testing.T) {
+	tests := []struct {
+		pattern, route string
+		want           bool
+	}{
+		{"*", "/api/v1/tags", true},
+		{"/api/v1/*", "/api/v1/tags/:id", true},
+		{"/api/v1/*", "/api/v2/tags", false},
+		{"/api/v1/*/:id", "/api/v1/tags/:id", true},
+		{"/api/v1/*/:id", "/api/v1/tags/:id/state", false},
+		{"/api/v1/tags", "/api/v1/tags", true},
+		{"/api/v1/tags", "/api/v1/tags/:id", false},
+	}
+	for _, tt := range tests {
+		if got := matchPattern(tt.pattern, tt.route); got != tt.want {
+			t.Errorf("matchPattern(%q, %q) = %v, want %v", tt.pattern, tt.route, got, tt.want)
+		}
+	}
+}
+
+func TestRuleSetMatch(t *testing.T) {
+	rule := func(method, key string, capacity int64) LimiterBucketRule {
+		return LimiterBucketRule{Method: method, Key: key, FillInterval: time.Second, Capacity: capacity, Quantum: 1}
+	}
+	rules := newRuleSet()
+	rules.add(rule("get", "/api/v1/tags/:id", 1))
+	rules.add(rule("", "/api/v1/tags/:id", 2))
+	rules.add(rule("POST", "/api/v1/*", 3))
+	rules.add(rule("*", "*", 4))
+	rules.add(rule("GET", "/disabled", 0))
+
+	tests := []struct {
+		method, route string
+		want          int64
+	}{
+		{"GET", "/api/v1/tags/:id", 1},    //方法和路由都精确匹配
+		{"DELETE", "/api/v1/tags/:id", 2}, //方法为空的精确规则
+		{"POST", "/api/v1/articles", 3},   //方法匹配的通配规则
+		{"PUT", "/api/v1/articles", 4},    //默认规则
+		{"GET", "/disabled", 4},           //容量为 0 的规则被忽略
+	}
+	for _, tt := range tests {
+		got, ok := rules.match(tt.method, tt.route)
+		if !ok || got.Capacity != tt.want {
+			t.Errorf("match(%s %s) = %d, %v; want rule %d", tt.method, tt.route, got.Capacity, ok, tt.want)
+		}
+	}
+}
+
+func TestRuleSetNoDefault(t *testing.T) {
+	rules := newRuleSet()
+	rules.add(LimiterBucketRule{Key: "/auth", FillInterval: time.Second, Capacity: 1})
+	if _, ok := rules.match("GET", "/api/v1/tags"); ok {
+		t.Error("unmatched route got a rule")
+	}
+}
+
+// 同一路由模式下的不同路径共用一个令牌桶
+func TestMethodLimiterKeyUsesRoutePattern(t *testing.T) {
+	l := NewMethodLimiter().AddBuckets(LimiterBucketRule{
+		Key: "/api/v1/tags/:id", FillInterval: time.Hour, Capacity: 1, Quantum: 1,
+	})
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	var keys []string
+	r.GET("/api/v1/tags/:id", func(c *gin.Context) {
+		key := l.Key(c)
+		keys = append(keys, key)
+		bucket, ok := l.GetBucket(key)
+		if !ok || bucket.TakeAvailable(1) == 0 {
+			c.Status(http.StatusTooManyRequests)
+			return
+		}
+		c.Status(http.StatusOK)
+	})
+
+	codes := make([]int, 0, 2)
+	for _, path := range []string{"/api/v1/tags/1", "/api/v1/tags/2"} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		codes = append(codes, w.Code)
+	}
+	if keys[0] != "GET /api/v1/tags/:id" || keys[0] != keys[1] {
+		t.Errorf("keys = %q, want both GET /api/v1/tags/:id", keys)
+	}
+	if codes[0] != http.StatusOK || codes[1] != http.StatusTooManyRequests {
+		t.Errorf("codes = %v, want second request limited", codes)
+	}
+}
+
+func TestParseKeyBy(t *testing.T) {
+	keyFuncs, err := ParseKeyBy([]string{"ip", "app_key"})
+	if err != nil || len(keyFuncs) != 2 {
+		t.Fatalf("ParseKeyBy(ip, app_key) = %d, %v", len(keyFuncs), err)
+	}
+	if _, err := ParseKeyBy([]string{"ip", "user"}); err == nil {
+		t.Error("ParseKeyBy accepted unknown value user")
+	}
+}
+
+// juju 每个 FillInterval 一次性补充 Quantum 个令牌，需要等到下一次补充才能放行
+func TestLocalBucketTiming(t *testing.T) {
+	tests := []struct {
+		name               string
+		rule               LimiterBucketRule
+		take               int64
+		minRetry, maxRetry time.Duration
+		minReset, maxReset time.Duration
+	}{
+		{
+			name:     "whole quantum per minute",
+			rule:     LimiterBucketRule{FillInterval: time.Minute, Capacity: 60, Quantum: 60},
+			take:     60,
+			minRetry: 59 * time.Second, maxRetry: time.Minute,
+			minReset: 59 * time.Second, maxReset: time.Minute,
+		},
+		{
+			name:     "several fills to reset",
+			rule:     LimiterBucketRule{FillInterval: 10 * time.Second, Capacity: 10, Quantum: 2},
+			take:     10,
+			minRetry: 9 * time.Second, maxRetry: 10 * time.Second,
+			minReset: 49 * time.Second, maxReset: 50 * time.Second,
+		},
+		{
+			name:     "tokens left",
+			rule:     LimiterBucketRule{FillInterval: 10 * time.Second, Capacity: 10, Quantum: 5},
+			take:     3,
+			minRetry: 0, maxRetry: 0,
+			minReset: 9 * time.Second, maxReset: 10 * time.Second,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newLocalBucket(tt.rule)
+			b.TakeAvailable(tt.take)
+			retry, reset, ok := b.Timing()
+			if !ok {
+				t.Fatal("Timing() ok = false")
+			}
+			if retry < tt.minRetry || retry > tt.maxRetry {
+				t.Errorf("retry after = %v, want between %v and %v", retry, tt.minRetry, tt.maxRetry)
+			}
+			if reset < tt.minReset || reset > tt.maxReset {
+				t.Errorf("reset after = %v, want between %v and %v", reset, tt.minReset, tt.maxReset)
+			}
+		})
+	}
+}
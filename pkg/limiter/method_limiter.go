@@ -1,48 +1,29 @@
 package limiter
 
-import (
-	"strings"
-
-	"github.com/gin-gonic/gin"
-	"github.com/juju/ratelimit"
-)
+import "github.com/gin-gonic/gin"
 
+// 按路由模式和请求方法限流，同一路由的所有客户端共用一个令牌桶
 type MethodLimiter struct {
 	*Limiter
 }
 
 func NewMethodLimiter() LimiterIface {
-	l := &Limiter{limiterBuckets: make(map[string]*ratelimit.Bucket)}
 	return MethodLimiter{
-		Limiter: l,
+		Limiter: newLimiter(0, 0),
 	}
 }
 
-func (l MethodLimiter) Key(c *gin.Context) string{
-	uri := c.Request.RequestURI
-	index := strings.Index(uri,"?")
-	if index == -1 {
-		return uri
-	}
-
-	return uri[:index]
+func (l MethodLimiter) Key(c *gin.Context) string {
+	return routeKey(c)
 }
 
-func (l MethodLimiter) GetBucket(key string) (*ratelimit.Bucket,bool){
-	bucket, ok := l.limiterBuckets[key]
-	return bucket,ok
+func (l MethodLimiter) GetBucket(key string) (Bucket, bool) {
+	return l.getBucket(key, key)
 }
 
-func (l MethodLimiter)AddBuckets(rules ...LimiterBucketRule) LimiterIface{
-	for _,rule := range rules {
-		if _,ok := l.limiterBuckets[rule.Key]; !ok {
-			bucket := ratelimit.NewBucketWithQuantum(
-				rule.FillInterval,
-				rule.Capacity,
-				rule.Quantum,
-			)
-			l.limiterBuckets[rule.Key] = bucket
-		}
+func (l MethodLimiter) AddBuckets(rules ...LimiterBucketRule) LimiterIface {
+	for _, rule := range rules {
+		l.rules.add(rule)
 	}
 	return l
-}
\ No newline at end of file
+}
@@ -0,0 +1,125 @@
+package limiter
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// GCRA 单次计算的结果
+type StoreResult struct {
+	Allowed bool
+	// 本次计算之后剩余的令牌数
+	Remaining int64
+	// 被拒绝时距离下一次可放行的时间
+	RetryAfter time.Duration
+	// 距离令牌桶恢复满额的时间
+	ResetAfter time.Duration
+}
+
+// 分布式限流使用的共享存储。
+// emission 为产生一个令牌的间隔，capacity 为突发容量，count 为 0 时只查询不消耗。
+type Store interface {
+	GCRA(ctx context.Context, key string, emission time.Duration, capacity, count int64) (StoreResult, error)
+}
+
+// 基于 Redis Lua 脚本的 GCRA 实现，时间取自 Redis 服务端，避免各副本时钟不一致
+var gcraScript = redis.NewScript(`
+if redis.replicate_commands then redis.replicate_commands() end
+local emission = tonumber(ARGV[1])
+local capacity = tonumber(ARGV[2])
+local count = tonumber(ARGV[3])
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000000 + tonumber(t[2])
+local tolerance = emission * capacity
+local tat = tonumber(redis.call('GET', KEYS[1]) or now)
+if tat < now then
+  tat = now
+end
+local new_tat = tat + emission * count
+local allow_at = new_tat - tolerance
+if now < allow_at then
+  return {0, math.floor((tolerance - (tat - now)) / emission), allow_at - now, tat - now}
+end
+if count > 0 then
+  redis.call('SET', KEYS[1], new_tat, 'PX', math.ceil((new_tat - now) / 1000))
+end
+return {1, math.floor((tolerance - (new_tat - now)) / emission), 0, new_tat - now}
+`)
+
+type RedisStore struct {
+	client redis.Scripter
+}
+
+// client 可以是 *redis.Client、*redis.ClusterClient 等任意兼容 Redis 协议的客户端
+func NewRedisStore(client redis.Scripter) *RedisStore {
+	return &RedisStore{client: client}
+}
+
+func (s *RedisStore) GCRA(ctx context.Context, key string, emission time.Duration, capacity, count int64) (StoreResult, error) {
+	vals, err := gcraScript.Run(ctx, s.client, []string{key},
+		emission.Microseconds(), capacity, count,
+	).Int64Slice()
+	if err != nil {
+		return StoreResult{}, err
+	}
+	return StoreResult{
+		Allowed:    vals[0] == 1,
+		Remaining:  vals[1],
+		RetryAfter: time.Duration(vals[2]) * time.Microsecond,
+		ResetAfter: time.Duration(vals[3]) * time.Microsecond,
+	}, nil
+}
+
+// 进程内的 GCRA 实现，与 RedisStore 语义一致，用于测试或单实例部署
+type MemoryStore struct {
+	mu        sync.Mutex
+	tat       map[string]time.Time
+	now       func() time.Time
+	lastSweep time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{tat: make(map[string]time.Time), now: time.Now}
+}
+
+func (s *MemoryStore) GCRA(ctx context.Context, key string, emission time.Duration, capacity, count int64) (StoreResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	tolerance := emission * time.Duration(capacity)
+	tat, ok := s.tat[key]
+	if !ok || tat.Before(now) {
+		tat = now
+	}
+	newTat := tat.Add(emission * time.Duration(count))
+	allowAt := newTat.Add(-tolerance)
+	if now.Before(allowAt) {
+		return StoreResult{
+			Allowed:    false,
+			Remaining:  int64((tolerance - tat.Sub(now)) / emission),
+			RetryAfter: allowAt.Sub(now),
+			ResetAfter: tat.Sub(now),
+		}, nil
+	}
+	if count > 0 {
+		s.tat[key] = newTat
+	}
+	// 定期清理已经恢复满额的记录
+	if now.Sub(s.lastSweep) > time.Minute {
+		s.lastSweep = now
+		for k, v := range s.tat {
+			if !v.After(now) {
+				delete(s.tat, k)
+			}
+		}
+	}
+	return StoreResult{
+		Allowed:    true,
+		Remaining:  int64((tolerance - newTat.Sub(now)) / emission),
+		ResetAfter: newTat.Sub(now),
+	}, nil
+}
@@ -0,0 +1,155 @@
+package limiter
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// 可以手动推进时间的 MemoryStore
+func newTestMemoryStore() (*MemoryStore, *time.Time) {
+	now := time.Unix(1700000000, 0)
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+	return store, &now
+}
+
+func TestMemoryStoreGCRA(t *testing.T) {
+	store, now := newTestMemoryStore()
+	ctx := context.Background()
+	emission, capacity := time.Second, int64(3)
+
+	// 突发容量内全部放行，剩余令牌依次减少
+	for want := capacity - 1; want >= 0; want-- {
+		result, err := store.GCRA(ctx, "k", emission, capacity, 1)
+		if err != nil || !result.Allowed {
+			t.Fatalf("request with %d remaining: %+v, %v", want+1, result, err)
+		}
+		if result.Remaining != want {
+			t.Errorf("Remaining = %d, want %d", result.Remaining, want)
+		}
+	}
+	if result, _ := store.GCRA(ctx, "k", emission, capacity, 1); result.ResetAfter != 3*time.Second {
+		t.Errorf("ResetAfter = %v, want 3s", result.ResetAfter)
+	}
+
+	*now = now.Add(400 * time.Millisecond)
+	result, _ := store.GCRA(ctx, "k", emission, capacity, 1)
+	if result.Allowed {
+		t.Fatal("request over capacity was allowed")
+	}
+	if result.RetryAfter != 600*time.Millisecond {
+		t.Errorf("RetryAfter = %v, want 600ms", result.RetryAfter)
+	}
+	if result.Remaining != 0 {
+		t.Errorf("Remaining = %d, want 0", result.Remaining)
+	}
+
+	*now = now.Add(result.RetryAfter)
+	if result, _ := store.GCRA(ctx, "k", emission, capacity, 1); !result.Allowed {
+		t.Error("request after RetryAfter was rejected")
+	}
+	// 其他键不受影响
+	if result, _ := store.GCRA(ctx, "other", emission, capacity, 1); !result.Allowed || result.Remaining != capacity-1 {
+		t.Errorf("other key: %+v", result)
+	}
+}
+
+func TestMemoryStoreQueryDoesNotConsume(t *testing.T) {
+	store, _ := newTestMemoryStore()
+	ctx := context.Background()
+	store.GCRA(ctx, "k", time.Second, 2, 1)
+	for i := 0; i < 3; i++ {
+		result, _ := store.GCRA(ctx, "k", time.Second, 2, 0)
+		if result.Remaining != 1 {
+			t.Fatalf("query %d: Remaining = %d, want 1", i, result.Remaining)
+		}
+	}
+}
+
+func TestStoreLimiterUsesStore(t *testing.T) {
+	store, _ := newTestMemoryStore()
+	rule := LimiterBucketRule{Key: "/auth", FillInterval: time.Second, Capacity: 2, Quantum: 1}
+	// 两个副本共用同一个存储，配额也共用
+	replicas := []LimiterIface{
+		NewStoreLimiter(store, StoreLimiterOptions{}).AddBuckets(rule),
+		NewStoreLimiter(store, StoreLimiterOptions{}).AddBuckets(rule),
+	}
+	var allowed int64
+	for i := 0; i < 4; i++ {
+		l := replicas[i%2]
+		bucket, ok := l.GetBucket(l.Key(newTestContext("GET", "/auth", "10.0.0.1:1")))
+		if !ok {
+			t.Fatal("no bucket for /auth")
+		}
+		allowed += bucket.TakeAvailable(1)
+	}
+	if allowed != 2 {
+		t.Errorf("allowed = %d across replicas, want 2", allowed)
+	}
+}
+
+type failingStore struct {
+	calls atomic.Int64
+}
+
+func (s *failingStore) GCRA(context.Context, string, time.Duration, int64, int64) (StoreResult, error) {
+	s.calls.Add(1)
+	return StoreResult{}, errors.New("connection refused")
+}
+
+func TestStoreLimiterFallsBackToLocal(t *testing.T) {
+	store := &failingStore{}
+	var storeErrors int
+	l := NewStoreLimiter(store, StoreLimiterOptions{
+		RetryInterval: time.Hour,
+		OnStoreError:  func(error) { storeErrors++ },
+	}).AddBuckets(LimiterBucketRule{Key: "/auth", FillInterval: time.Hour, Capacity: 1, Quantum: 1})
+
+	key := l.Key(newTestContext("GET", "/auth", "10.0.0.1:1"))
+	take := func() int64 {
+		bucket, ok := l.GetBucket(key)
+		if !ok {
+			t.Fatal("no bucket for /auth")
+		}
+		return bucket.TakeAvailable(1)
+	}
+	if take() != 1 {
+		t.Fatal("first request was rejected while falling back")
+	}
+	if take() != 0 {
+		t.Error("local bucket did not limit the second request")
+	}
+	// 存储失败后在 RetryInterval 内不再访问存储
+	if n := store.calls.Load(); n != 1 {
+		t.Errorf("store was called %d times, want 1", n)
+	}
+	if storeErrors != 1 {
+		t.Errorf("OnStoreError called %d times, want 1", storeErrors)
+	}
+}
+
+func TestStoreBucketTiming(t *testing.T) {
+	store, now := newTestMemoryStore()
+	l := NewStoreLimiter(store, StoreLimiterOptions{}).
+		AddBuckets(LimiterBucketRule{Key: "/auth", FillInterval: 10 * time.Second, Capacity: 1, Quantum: 1})
+	key := l.Key(newTestContext("GET", "/auth", "10.0.0.1:1"))
+
+	bucket, _ := l.GetBucket(key)
+	// 尚未访问存储时使用本地令牌桶，桶是满的
+	if retry, reset, ok := bucket.(TimedBucket).Timing(); !ok || retry != 0 || reset != 0 {
+		t.Errorf("Timing() before the store was called = %v, %v, %v; want 0, 0, true", retry, reset, ok)
+	}
+	bucket.TakeAvailable(1)
+	*now = now.Add(6 * time.Second)
+	bucket, _ = l.GetBucket(key)
+	if bucket.TakeAvailable(1) != 0 {
+		t.Fatal("request over capacity was allowed")
+	}
+	retry, reset, ok := bucket.(TimedBucket).Timing()
+	if !ok || retry != 4*time.Second || reset != 4*time.Second {
+		t.Errorf("Timing() = %v, %v, %v; want 4s, 4s, true", retry, reset, ok)
+	}
+}
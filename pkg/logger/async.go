@@ -0,0 +1,234 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// 缓冲区满时的处理策略
+type OverflowPolicy string
+
+const (
+	// 阻塞调用方直到缓冲区有空位，不丢日志
+	OverflowBlock OverflowPolicy = "block"
+	// 直接丢弃当前日志，不影响请求耗时
+	OverflowDrop OverflowPolicy = "drop"
+)
+
+const (
+	defaultAsyncBufferSize     = 1024
+	defaultAsyncBatchSize      = 128
+	defaultAsyncReportInterval = 10 * time.Second
+)
+
+// 写入失败和丢弃日志的提示输出到这里，不经过日志本身
+var errOutput io.Writer = os.Stderr
+
+var ErrAsyncWriterClosed = errors.New("logger: async writer is closed")
+
+type AsyncOptions struct {
+	// 缓冲的日志条数
+	BufferSize int
+	// 单次批量写入的最大条数
+	BatchSize int
+	Policy    OverflowPolicy
+	// 丢弃日志后，每隔多久输出一次丢弃的条数
+	ReportInterval time.Duration
+}
+
+type asyncEntry struct {
+	level Level
+	// 为 false 时按普通 Write 写入，不区分级别
+	leveled bool
+	line    []byte
+}
+
+// 异步写入器：日志先进入有界缓冲区，由后台 goroutine 批量写入下游
+type AsyncWriter struct {
+	w       io.Writer
+	opts    AsyncOptions
+	entries chan asyncEntry
+	flushes chan chan struct{}
+	done    chan struct{}
+	dropped atomic.Uint64
+	// 上次报告时的丢弃条数，只在后台 goroutine 中访问
+	reported uint64
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+func NewAsyncWriter(w io.Writer, opts AsyncOptions) *AsyncWriter {
+	if opts.BufferSize <= 0 {
+		opts.BufferSize = defaultAsyncBufferSize
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = defaultAsyncBatchSize
+	}
+	if opts.Policy == "" {
+		opts.Policy = OverflowBlock
+	}
+	if opts.ReportInterval <= 0 {
+		opts.ReportInterval = defaultAsyncReportInterval
+	}
+	a := &AsyncWriter{
+		w:       w,
+		opts:    opts,
+		entries: make(chan asyncEntry, opts.BufferSize),
+		flushes: make(chan chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go a.run()
+	return a
+}
+
+func (a *AsyncWriter) Write(p []byte) (int, error) {
+	return a.enqueue(asyncEntry{line: p})
+}
+
+func (a *AsyncWriter) WriteLevel(level Level, p []byte) (int, error) {
+	return a.enqueue(asyncEntry{level: level, leveled: true, line: p})
+}
+
+// 因缓冲区已满而被丢弃的日志条数
+func (a *AsyncWriter) Dropped() uint64 {
+	return a.dropped.Load()
+}
+
+func (a *AsyncWriter) enqueue(e asyncEntry) (int, error) {
+	n := len(e.line)
+	// 调用方可能复用 p，这里需要拷贝一份
+	e.line = append([]byte(nil), e.line...)
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if a.closed {
+		return 0, ErrAsyncWriterClosed
+	}
+	if a.opts.Policy == OverflowDrop {
+		select {
+		case a.entries <- e:
+		default:
+			a.dropped.Add(1)
+		}
+		return n, nil
+	}
+	a.entries <- e
+	return n, nil
+}
+
+// 等待缓冲区中已有的日志全部写入下游
+func (a *AsyncWriter) Flush() error {
+	a.mu.RLock()
+	if a.closed {
+		a.mu.RUnlock()
+		return nil
+	}
+	ack := make(chan struct{})
+	a.flushes <- ack
+	a.mu.RUnlock()
+	<-ack
+	return nil
+}
+
+// 写完缓冲区中剩余的日志后关闭下游
+func (a *AsyncWriter) Close() error {
+	a.mu.Lock()
+	if a.closed {
+		a.mu.Unlock()
+		return nil
+	}
+	a.closed = true
+	close(a.entries)
+	a.mu.Unlock()
+
+	<-a.done
+	if c, ok := a.w.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+func (a *AsyncWriter) run() {
+	defer close(a.done)
+	defer a.reportDropped()
+	ticker := time.NewTicker(a.opts.ReportInterval)
+	defer ticker.Stop()
+	batch := make([]asyncEntry, 0, a.opts.BatchSize)
+	for {
+		select {
+		case e, ok := <-a.entries:
+			if !ok {
+				return
+			}
+			batch = append(batch[:0], e)
+			batch = a.fill(batch, a.opts.BatchSize)
+			a.write(batch)
+		case ack := <-a.flushes:
+			// 只写请求 Flush 时已在缓冲区中的日志，持续写入时也能返回
+			for n := len(a.entries); n > 0; n -= len(batch) {
+				batch = a.fill(batch[:0], min(n, a.opts.BatchSize))
+				if len(batch) == 0 {
+					break
+				}
+				a.write(batch)
+			}
+			close(ack)
+		case <-ticker.C:
+			a.reportDropped()
+		}
+	}
+}
+
+// 不阻塞地从缓冲区取出更多日志，直到达到 limit 条
+func (a *AsyncWriter) fill(batch []asyncEntry, limit int) []asyncEntry {
+	for len(batch) < limit {
+		select {
+		case e, ok := <-a.entries:
+			if !ok {
+				return batch
+			}
+			batch = append(batch, e)
+		default:
+			return batch
+		}
+	}
+	return batch
+}
+
+// 普通写入器合并为一次 Write，需要级别的写入器逐条写入
+func (a *AsyncWriter) write(batch []asyncEntry) {
+	lw, isLevelWriter := a.w.(LevelWriter)
+	var buf bytes.Buffer
+	for _, e := range batch {
+		if e.leveled && isLevelWriter {
+			if _, err := lw.WriteLevel(e.level, e.line); err != nil {
+				fmt.Fprintf(errOutput, "logger: async write err: %v\n", err)
+			}
+			continue
+		}
+		buf.Write(e.line)
+	}
+	if buf.Len() > 0 {
+		if _, err := a.w.Write(buf.Bytes()); err != nil {
+			fmt.Fprintf(errOutput, "logger: async write err: %v\n", err)
+		}
+	}
+}
+
+// 输出自上次报告以来丢弃的条数
+func (a *AsyncWriter) reportDropped() {
+	dropped := a.dropped.Load()
+	if dropped == a.reported {
+		return
+	}
+	fmt.Fprintf(errOutput, "logger: async writer dropped %d log entries (%d in total), buffer is full\n",
+		dropped-a.reported, dropped)
+	a.reported = dropped
+}
@@ -0,0 +1,317 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// 对比同步写入与异步写入在请求路径上的耗时，输出目标为真实的文件：
+//
+//	go test -run=^$ -bench=Logger -benchmem ./pkg/logger
+func BenchmarkLogger(b *testing.B) {
+	benchmarks := []struct {
+		name  string
+		async *AsyncOptions
+	}{
+		{name: "sync"},
+		{name: "async_block", async: &AsyncOptions{Policy: OverflowBlock}},
+		{name: "async_drop", async: &AsyncOptions{Policy: OverflowDrop}},
+	}
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			l, closeFn := newBenchLogger(b, bm.async)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				l.Infof("request handled: %s %d", "/api/v1/tags", i)
+			}
+			b.StopTimer()
+			closeFn()
+		})
+		b.Run(bm.name+"_parallel", func(b *testing.B) {
+			l, closeFn := newBenchLogger(b, bm.async)
+			b.ReportAllocs()
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					l.Infof("request handled: %s", "/api/v1/tags")
+				}
+			})
+			b.StopTimer()
+			closeFn()
+		})
+	}
+}
+
+func newBenchLogger(b *testing.B, async *AsyncOptions) (*Logger, func()) {
+	b.Helper()
+	f, err := os.Create(filepath.Join(b.TempDir(), "bench.log"))
+	if err != nil {
+		b.Fatal(err)
+	}
+	var w io.Writer = f
+	var aw *AsyncWriter
+	if async != nil {
+		aw = NewAsyncWriter(f, *async)
+		w = aw
+	}
+	l := NewLogger(NewSink(w, FormatJSON)).WithFields(Fields{"component": "bench"})
+	return l, func() {
+		if aw != nil {
+			if err := aw.Close(); err != nil {
+				b.Error(err)
+			}
+			if n := aw.Dropped(); n > 0 {
+				b.ReportMetric(float64(n)/float64(b.N), "dropped/op")
+			}
+		}
+		f.Close()
+	}
+}
+
+// 记录每次 Write 的内容；gate 不为 nil 时每次写入前等待放行
+type recordWriter struct {
+	mu     sync.Mutex
+	gate   chan struct{}
+	writes []string
+	levels []Level
+	closed bool
+}
+
+func (w *recordWriter) Write(p []byte) (int, error) {
+	if w.gate != nil {
+		<-w.gate
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.writes = append(w.writes, string(p))
+	return len(p), nil
+}
+
+func (w *recordWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.closed = true
+	return nil
+}
+
+func (w *recordWriter) Writes() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]string(nil), w.writes...)
+}
+
+type recordLevelWriter struct {
+	recordWriter
+}
+
+func (w *recordLevelWriter) WriteLevel(level Level, p []byte) (int, error) {
+	w.mu.Lock()
+	w.levels = append(w.levels, level)
+	w.mu.Unlock()
+	return w.Write(p)
+}
+
+// 下游阻塞时填满缓冲区：drop 策略立即返回并计数，block 策略等到下游放行
+func TestAsyncWriterOverflow(t *testing.T) {
+	tests := []struct {
+		policy      OverflowPolicy
+		wantBlock   bool
+		wantDropped uint64
+	}{
+		{OverflowDrop, false, 2},
+		{OverflowBlock, true, 0},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.policy), func(t *testing.T) {
+			w := &recordWriter{gate: make(chan struct{})}
+			a := NewAsyncWriter(w, AsyncOptions{BufferSize: 1, BatchSize: 1, Policy: tt.policy})
+			// 第一条被后台 goroutine 取出并阻塞在下游，第二条占满缓冲区
+			a.Write([]byte("1\n"))
+			waitFor(t, func() bool { return len(a.entries) == 0 })
+			a.Write([]byte("2\n"))
+
+			done := make(chan struct{})
+			go func() {
+				a.Write([]byte("3\n"))
+				a.Write([]byte("4\n"))
+				close(done)
+			}()
+			select {
+			case <-done:
+				if tt.wantBlock {
+					t.Fatal("Write returned while the buffer was full")
+				}
+			case <-time.After(50 * time.Millisecond):
+				if !tt.wantBlock {
+					t.Fatal("Write blocked with the drop policy")
+				}
+			}
+			close(w.gate)
+			<-done
+			if err := a.Close(); err != nil {
+				t.Fatal(err)
+			}
+			if got := a.Dropped(); got != tt.wantDropped {
+				t.Errorf("Dropped() = %d, want %d", got, tt.wantDropped)
+			}
+			if got, want := len(w.Writes()), 4-int(tt.wantDropped); got != want {
+				t.Errorf("wrote %d entries, want %d", got, want)
+			}
+		})
+	}
+}
+
+// 下游阻塞期间积压的日志按 BatchSize 合并写入
+func TestAsyncWriterBatching(t *testing.T) {
+	tests := []struct {
+		batchSize int
+		want      []string
+	}{
+		{1, []string{"a\n", "b\n", "c\n", "d\n"}},
+		{2, []string{"a\n", "b\nc\n", "d\n"}},
+		{8, []string{"a\n", "b\nc\nd\n"}},
+	}
+	for _, tt := range tests {
+		t.Run(strconv.Itoa(tt.batchSize), func(t *testing.T) {
+			w := &recordWriter{gate: make(chan struct{})}
+			a := NewAsyncWriter(w, AsyncOptions{BatchSize: tt.batchSize})
+			a.Write([]byte("a\n"))
+			waitFor(t, func() bool { return len(a.entries) == 0 })
+			for _, line := range []string{"b\n", "c\n", "d\n"} {
+				a.Write([]byte(line))
+			}
+			close(w.gate)
+			if err := a.Flush(); err != nil {
+				t.Fatal(err)
+			}
+			if got := w.Writes(); !slices.Equal(got, tt.want) {
+				t.Errorf("writes = %q, want %q", got, tt.want)
+			}
+			a.Close()
+		})
+	}
+}
+
+func TestAsyncWriterFlushAndClose(t *testing.T) {
+	w := &recordWriter{}
+	a := NewAsyncWriter(w, AsyncOptions{})
+	a.Write([]byte("a\n"))
+	if err := a.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(w.Writes(), ""); got != "a\n" {
+		t.Errorf("after Flush wrote %q, want %q", got, "a\n")
+	}
+
+	for i := 0; i < 100; i++ {
+		a.Write([]byte("b\n"))
+	}
+	if err := a.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Count(strings.Join(w.Writes(), ""), "b\n"); got != 100 {
+		t.Errorf("Close drained %d entries, want 100", got)
+	}
+	if !w.closed {
+		t.Error("Close did not close the downstream writer")
+	}
+	if _, err := a.Write([]byte("c\n")); !errors.Is(err, ErrAsyncWriterClosed) {
+		t.Errorf("Write after Close err = %v, want ErrAsyncWriterClosed", err)
+	}
+	if err := a.Flush(); err != nil {
+		t.Errorf("Flush after Close err = %v, want nil", err)
+	}
+	if err := a.Close(); err != nil {
+		t.Errorf("second Close err = %v, want nil", err)
+	}
+}
+
+// 持续写入时 Flush 只等待调用时已缓冲的日志
+func TestAsyncWriterFlushUnderLoad(t *testing.T) {
+	a := NewAsyncWriter(io.Discard, AsyncOptions{BufferSize: 16, BatchSize: 4})
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				a.Write([]byte("x\n"))
+			}
+		}
+	}()
+	done := make(chan error)
+	go func() { done <- a.Flush() }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Error(err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Error("Flush did not return under continuous writes")
+	}
+	close(stop)
+	wg.Wait()
+	a.Close()
+}
+
+// 带级别的写入逐条传给 LevelWriter（如 syslog），普通写入仍按 Write 合并
+func TestAsyncWriterPassesLevel(t *testing.T) {
+	w := &recordLevelWriter{}
+	a := NewAsyncWriter(w, AsyncOptions{})
+	a.WriteLevel(LevelWarn, []byte("warn\n"))
+	a.WriteLevel(LevelError, []byte("error\n"))
+	a.Write([]byte("plain\n"))
+	if err := a.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if want := []Level{LevelWarn, LevelError}; !slices.Equal(w.levels, want) {
+		t.Errorf("levels = %v, want %v", w.levels, want)
+	}
+	if want := []string{"warn\n", "error\n", "plain\n"}; !slices.Equal(w.Writes(), want) {
+		t.Errorf("writes = %q, want %q", w.Writes(), want)
+	}
+}
+
+func TestAsyncWriterReportsDropped(t *testing.T) {
+	var out bytes.Buffer
+	errOutput = &out
+	t.Cleanup(func() { errOutput = os.Stderr })
+
+	w := &recordWriter{gate: make(chan struct{})}
+	a := NewAsyncWriter(w, AsyncOptions{BufferSize: 1, BatchSize: 1, Policy: OverflowDrop, ReportInterval: time.Hour})
+	a.Write([]byte("1\n"))
+	waitFor(t, func() bool { return len(a.entries) == 0 })
+	for i := 0; i < 4; i++ {
+		a.Write([]byte("x\n"))
+	}
+	close(w.gate)
+	a.Close()
+	if want := "dropped 3 log entries (3 in total)"; !strings.Contains(out.String(), want) {
+		t.Errorf("report = %q, want it to contain %q", out.String(), want)
+	}
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met within 1s")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,35 @@
+package logger
+
+import "context"
+
+type contextKey struct{}
+
+// 随请求上下文传递的日志字段，Principal 在鉴权之后才会被填充
+type RequestInfo struct {
+	RequestID string
+	Method    string
+	Path      string
+	Principal string
+}
+
+func NewContext(ctx context.Context, info *RequestInfo) context.Context {
+	return context.WithValue(ctx, contextKey{}, info)
+}
+
+func FromContext(ctx context.Context) (*RequestInfo, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	info, ok := ctx.Value(contextKey{}).(*RequestInfo)
+	return info, ok && info != nil
+}
+
+// 将上下文中的请求信息写入日志字段
+func (info *RequestInfo) fields(data Fields) {
+	data["request_id"] = info.RequestID
+	data["method"] = info.Method
+	data["path"] = info.Path
+	if info.Principal != "" {
+		data["principal"] = info.Principal
+	}
+}
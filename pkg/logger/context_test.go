@@ -0,0 +1,63 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestRequestInfoFields(t *testing.T) {
+	tests := []struct {
+		name string
+		info *RequestInfo
+		want Fields
+	}{
+		{
+			name: "anonymous request",
+			info: &RequestInfo{RequestID: "req-1", Method: "GET", Path: "/api/v1/tags"},
+			want: Fields{"request_id": "req-1", "method": "GET", "path": "/api/v1/tags"},
+		},
+		{
+			name: "authenticated request",
+			info: &RequestInfo{RequestID: "req-2", Method: "POST", Path: "/api/v1/tags", Principal: "editor"},
+			want: Fields{"request_id": "req-2", "method": "POST", "path": "/api/v1/tags", "principal": "editor"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			l := NewLogger(NewSink(&buf, FormatJSON))
+			l.WithContext(NewContext(context.Background(), tt.info)).Info("handled")
+
+			var got Fields
+			if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+				t.Fatal(err)
+			}
+			for k, v := range tt.want {
+				if got[k] != v {
+					t.Errorf("%s = %v, want %v", k, got[k], v)
+				}
+			}
+			if _, ok := got["principal"]; ok && tt.info.Principal == "" {
+				t.Errorf("principal = %v, want it omitted", got["principal"])
+			}
+		})
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	if _, ok := FromContext(nil); ok {
+		t.Error("FromContext(nil) ok = true")
+	}
+	if _, ok := FromContext(context.Background()); ok {
+		t.Error("FromContext(empty) ok = true")
+	}
+	if _, ok := FromContext(NewContext(context.Background(), nil)); ok {
+		t.Error("FromContext(nil info) ok = true")
+	}
+	info := &RequestInfo{RequestID: "req-1"}
+	if got, ok := FromContext(NewContext(context.Background(), info)); !ok || got != info {
+		t.Errorf("FromContext = %v, %v, want the stored info", got, ok)
+	}
+}
@@ -0,0 +1,64 @@
+package logger
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// 错误链上最多记录的层数，防止循环引用的错误无限展开
+const maxErrorChainDepth = 32
+
+// 记录错误信息：error 为错误文本，error_type 为具体类型，
+// error_chain 为 errors.Unwrap 展开的整条错误链，stack 为调用 WithError 处的调用栈
+func (l *Logger) WithError(err error) *Logger {
+	if err == nil {
+		return l
+	}
+	return l.WithFields(Fields{
+		"error":       err.Error(),
+		"error_type":  fmt.Sprintf("%T", err),
+		"error_chain": errorChain(err),
+		"stack":       stack(3),
+	})
+}
+
+type errorLink struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+// 按广度优先展开错误链，同时支持 Unwrap() error 和 Unwrap() []error
+func errorChain(err error) []errorLink {
+	var chain []errorLink
+	queue := []error{err}
+	for len(queue) > 0 && len(chain) < maxErrorChainDepth {
+		e := queue[0]
+		queue = queue[1:]
+		if e == nil {
+			continue
+		}
+		chain = append(chain, errorLink{Type: fmt.Sprintf("%T", e), Message: e.Error()})
+		switch u := e.(type) {
+		case interface{ Unwrap() error }:
+			queue = append(queue, u.Unwrap())
+		case interface{ Unwrap() []error }:
+			queue = append(queue, u.Unwrap()...)
+		}
+	}
+	return chain
+}
+
+func stack(skip int) []string {
+	pcs := make([]uintptr, 32)
+	depth := runtime.Callers(skip, pcs)
+	frames := runtime.CallersFrames(pcs[:depth])
+	var callers []string
+	for {
+		frame, more := frames.Next()
+		callers = append(callers, fmt.Sprintf("%s: %d %s", frame.File, frame.Line, frame.Function))
+		if !more {
+			break
+		}
+	}
+	return callers
+}
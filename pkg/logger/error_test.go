@@ -0,0 +1,119 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+	"testing"
+)
+
+type chainEntry struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+type errorFields struct {
+	Error      string       `json:"error"`
+	ErrorType  string       `json:"error_type"`
+	ErrorChain []chainEntry `json:"error_chain"`
+	Stack      []string     `json:"stack"`
+}
+
+func TestWithError(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		wantType  string
+		wantChain []string
+	}{
+		{
+			name:      "plain error",
+			err:       errors.New("boom"),
+			wantType:  "*errors.errorString",
+			wantChain: []string{"boom"},
+		},
+		{
+			name:      "wrapped twice",
+			err:       fmt.Errorf("get tag 1: %w", fmt.Errorf("query: %w", fs.ErrNotExist)),
+			wantType:  "*fmt.wrapError",
+			wantChain: []string{"get tag 1: query: file does not exist", "query: file does not exist", "file does not exist"},
+		},
+		{
+			name:      "joined",
+			err:       errors.Join(errors.New("a"), fmt.Errorf("b: %w", errors.New("c"))),
+			wantType:  "*errors.joinError",
+			wantChain: []string{"a\nb: c", "a", "b: c", "c"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			NewLogger(NewSink(&buf, FormatJSON)).WithError(tt.err).Error("failed")
+
+			var got errorFields
+			if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+				t.Fatal(err)
+			}
+			if got.Error != tt.err.Error() || got.ErrorType != tt.wantType {
+				t.Errorf("error = %q (%s), want %q (%s)", got.Error, got.ErrorType, tt.err.Error(), tt.wantType)
+			}
+			var chain []string
+			for _, e := range got.ErrorChain {
+				chain = append(chain, e.Message)
+			}
+			if strings.Join(chain, "|") != strings.Join(tt.wantChain, "|") {
+				t.Errorf("error_chain = %q, want %q", chain, tt.wantChain)
+			}
+			// 调用栈从调用 WithError 的位置开始
+			if len(got.Stack) == 0 || !strings.Contains(got.Stack[0], "TestWithError") {
+				t.Errorf("stack[0] = %v, want the calling test function", got.Stack)
+			}
+		})
+	}
+}
+
+func TestWithErrorNil(t *testing.T) {
+	l := NewLogger()
+	if l.WithError(nil) != l {
+		t.Error("WithError(nil) returned a new logger")
+	}
+}
+
+// 循环引用的错误链在达到上限后停止展开
+type loopError struct{ next error }
+
+func (e *loopError) Error() string { return "loop" }
+func (e *loopError) Unwrap() error { return e.next }
+
+func TestErrorChainDepth(t *testing.T) {
+	e := &loopError{}
+	e.next = e
+	if got := len(errorChain(e)); got != maxErrorChainDepth {
+		t.Errorf("len(errorChain) = %d, want %d", got, maxErrorChainDepth)
+	}
+}
+
+// Log 按 fatal/panic 级别只记录日志，Panic 记录后才触发 panic
+func TestFatalPanicLevels(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewLogger(NewSink(&buf, FormatJSON))
+	l.Log(LevelFatal, "fatal logged")
+	l.Logf(LevelPanic, "panic %s", "logged")
+	if !strings.Contains(buf.String(), "fatal logged") || !strings.Contains(buf.String(), "panic logged") {
+		t.Fatalf("Log did not write both entries: %q", buf.String())
+	}
+
+	buf.Reset()
+	defer func() {
+		if r := recover(); r != "stop" {
+			t.Errorf("recover() = %v, want %q", r, "stop")
+		}
+		if !strings.Contains(buf.String(), `"level":"panic"`) {
+			t.Errorf("Panic did not log before panicking: %q", buf.String())
+		}
+	}()
+	l.Panic("stop")
+}
@@ -2,13 +2,14 @@ package logger
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"io"
-	"log"
 	"maps"
+	"os"
 	"runtime"
+	"sync"
 	"time"
+
+	"go.opentelemetry.io/otel/trace"
 )
 
 type Level int8
@@ -23,6 +24,9 @@ const (
 	LevelPanic
 )
 
+// ParseLevel 解析失败时返回，不对应任何级别
+const LevelInvalid Level = -1
+
 // 日志分级
 func (l Level) String() string {
 	switch l {
@@ -42,16 +46,98 @@ func (l Level) String() string {
 	return ""
 }
 
+// 只接受 String 返回的级别名称，与配置和管理接口的校验规则一致
+func ParseLevel(s string) (Level, error) {
+	for l := LevelDebug; l <= LevelPanic; l++ {
+		if s == l.String() {
+			return l, nil
+		}
+	}
+	return LevelInvalid, fmt.Errorf("logger: unknown level %q", s)
+}
+
+// 日志级别过滤，由同一个 Logger 派生出的所有 Logger 共享，支持运行时修改
+type levelFilter struct {
+	mu         sync.RWMutex
+	min        Level
+	components map[string]Level
+}
+
+func (f *levelFilter) enabled(component string, level Level) bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	if min, ok := f.components[component]; ok && component != "" {
+		return level >= min
+	}
+	return level >= f.min
+}
+
 type Logger struct {
-	newLogger *log.Logger
+	sinks     []*Sink
 	ctx       context.Context
 	fields    Fields
 	callers   []string
+	component string
+	levels    *levelFilter
+}
+
+// 同一条日志会按各自的格式写入所有输出目标
+func NewLogger(sinks ...*Sink) *Logger {
+	return &Logger{sinks: sinks, levels: &levelFilter{min: LevelDebug, components: map[string]Level{}}}
+}
+
+// 等待所有带缓冲的输出目标写完
+func (l *Logger) Flush() error {
+	var err error
+	for _, s := range l.sinks {
+		if e := s.Flush(); e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
+
+// 关闭所有输出目标，异步输出目标会先写完缓冲区中的日志
+func (l *Logger) Close() error {
+	var err error
+	for _, s := range l.sinks {
+		if e := s.Close(); e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
+
+// 设置最低输出级别
+func (l *Logger) SetLevel(level Level) {
+	l.levels.mu.Lock()
+	defer l.levels.mu.Unlock()
+	l.levels.min = level
+}
+
+// 设置某个组件的最低输出级别，覆盖全局级别
+func (l *Logger) SetComponentLevel(component string, level Level) {
+	l.levels.mu.Lock()
+	defer l.levels.mu.Unlock()
+	l.levels.components[component] = level
+}
+
+// 删除组件的级别覆盖，恢复使用全局级别
+func (l *Logger) ResetComponentLevel(component string) {
+	l.levels.mu.Lock()
+	defer l.levels.mu.Unlock()
+	delete(l.levels.components, component)
+}
+
+// 返回全局级别和各组件的级别覆盖
+func (l *Logger) Levels() (Level, map[string]Level) {
+	l.levels.mu.RLock()
+	defer l.levels.mu.RUnlock()
+	return l.levels.min, maps.Clone(l.levels.components)
 }
 
-func NewLogger(w io.Writer, prefix string, flag int) *Logger {
-	l := log.New(w, prefix, flag)
-	return &Logger{newLogger: l}
+func (l *Logger) Enabled(level Level) bool {
+	return l.levels.enabled(l.component, level)
 }
 
 func (l *Logger) clone() *Logger {
@@ -73,6 +159,13 @@ func (l *Logger) WithFields(f Fields) *Logger {
 	return l1
 }
 
+// 设置日志所属组件，用于按组件配置日志级别
+func (l *Logger) WithComponent(component string) *Logger {
+	l1 := l.clone()
+	l1.component = component
+	return l1
+}
+
 // 设置日志上下文属性
 func (l *Logger) WithContext(ctx context.Context) *Logger {
 	l1 := l.clone()
@@ -112,11 +205,24 @@ func (l *Logger) WithCallersFrames() *Logger {
 }
 
 func (l *Logger) JSONFormat(level Level, message string) map[string]any {
-	data := make(Fields, len(l.fields)+4)
+	data := make(Fields, len(l.fields)+8)
 	data["level"] = level.String()
 	data["time"] = time.Now().Local().UnixNano()
 	data["message"] = message
 	data["callers"] = l.callers
+	if l.component != "" {
+		data["component"] = l.component
+	}
+	if info, ok := FromContext(l.ctx); ok {
+		info.fields(data)
+	}
+	//关联链路追踪，便于从日志跳转到对应的 trace
+	if l.ctx != nil {
+		if sc := trace.SpanContextFromContext(l.ctx); sc.IsValid() {
+			data["trace_id"] = sc.TraceID().String()
+			data["span_id"] = sc.SpanID().String()
+		}
+	}
 
 	if len(l.fields) > 0 {
 		for k, v := range l.fields {
@@ -128,25 +234,32 @@ func (l *Logger) JSONFormat(level Level, message string) map[string]any {
 	return data
 }
 
+// 只负责按级别输出日志，不会退出程序或触发 panic
 func (l *Logger) Output(level Level, message string) {
-	body, _ := json.Marshal(l.JSONFormat(level, message))
-	content := string(body)
-	switch level {
-	case LevelDebug:
-		l.newLogger.Print(content)
-	case LevelInfo:
-		l.newLogger.Print(content)
-	case LevelWarn:
-		l.newLogger.Print(content)
-	case LevelError:
-		l.newLogger.Print(content)
-	case LevelFatal:
-		l.newLogger.Fatal(content)
-	case LevelPanic:
-		l.newLogger.Panic(content)
+	if !l.Enabled(level) {
+		return
+	}
+	data := l.JSONFormat(level, message)
+	// 同一种格式只序列化一次
+	lines := make(map[Format][]byte, 2)
+	for _, s := range l.sinks {
+		line, ok := lines[s.format]
+		if !ok {
+			line = s.format.encode(level, data)
+			lines[s.format] = line
+		}
+		s.write(level, line)
 	}
 }
 
+// 按指定级别输出日志，fatal 和 panic 级别也只记录而不影响程序流程
+func (l *Logger) Log(level Level, v ...any) {
+	l.Output(level, fmt.Sprint(v...))
+}
+func (l *Logger) Logf(level Level, format string, v ...any) {
+	l.Output(level, fmt.Sprintf(format, v...))
+}
+
 // debug
 func (l *Logger) Debug(v ...any) {
 	l.Output(LevelDebug, fmt.Sprint(v...))
@@ -179,18 +292,31 @@ func (l *Logger) Errorf(format string, v ...any) {
 	l.Output(LevelError, fmt.Sprintf(format, v...))
 }
 
-// fatal
+// fatal：记录日志并写完缓冲后调用 os.Exit(1)，defer 不会被执行，
+// 只应在启动阶段等无法继续运行的场景使用，否则请使用 Log(LevelFatal, ...)
 func (l *Logger) Fatal(v ...any) {
-	l.Output(LevelFatal, fmt.Sprint(v...))
+	l.fatal(fmt.Sprint(v...))
 }
 func (l *Logger) Fatalf(format string, v ...any) {
-	l.Output(LevelFatal, fmt.Sprintf(format, v...))
+	l.fatal(fmt.Sprintf(format, v...))
 }
 
-// Panic
+// Panic：记录日志后触发 panic，不要在 recover 处理逻辑中使用
 func (l *Logger) Panic(v ...any) {
-	l.Output(LevelPanic, fmt.Sprint(v...))
+	l.panic(fmt.Sprint(v...))
 }
 func (l *Logger) Panicf(format string, v ...any) {
-	l.Output(LevelPanic, fmt.Sprintf(format, v...))
+	l.panic(fmt.Sprintf(format, v...))
+}
+
+func (l *Logger) fatal(message string) {
+	l.Output(LevelFatal, message)
+	_ = l.Flush()
+	os.Exit(1)
+}
+
+func (l *Logger) panic(message string) {
+	l.Output(LevelPanic, message)
+	_ = l.Flush()
+	panic(message)
 }
@@ -0,0 +1,118 @@
+package logger
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ludyyy-lu/goBlogService/pkg/setting"
+)
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Level
+		wantErr bool
+	}{
+		{"debug", LevelDebug, false},
+		{"info", LevelInfo, false},
+		{"warn", LevelWarn, false},
+		{"error", LevelError, false},
+		{"fatal", LevelFatal, false},
+		{"panic", LevelPanic, false},
+		{"warning", LevelInvalid, true},
+		{"INFO", LevelInvalid, true},
+		{" info", LevelInvalid, true},
+		{"", LevelInvalid, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseLevel(tt.in)
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("ParseLevel(%q) = %v, %v, want %v, err %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+// 配置校验接受的级别必须都能被 ParseLevel 解析，反之亦然
+func TestParseLevelMatchesSettingRule(t *testing.T) {
+	field, _ := reflect.TypeOf(setting.LogSettingS{}).FieldByName("Level")
+	var allowed []string
+	for _, rule := range strings.Split(field.Tag.Get("validate"), ",") {
+		if param, ok := strings.CutPrefix(rule, "oneof="); ok {
+			allowed = strings.Fields(param)
+		}
+	}
+	if len(allowed) == 0 {
+		t.Fatal("LogSettingS.Level has no oneof rule")
+	}
+	for _, name := range allowed {
+		if _, err := ParseLevel(name); err != nil {
+			t.Errorf("setting allows %q but ParseLevel rejects it: %v", name, err)
+		}
+	}
+	for l := LevelDebug; l <= LevelPanic; l++ {
+		if !strings.Contains(" "+strings.Join(allowed, " ")+" ", " "+l.String()+" ") {
+			t.Errorf("ParseLevel accepts %q but the setting rule rejects it", l.String())
+		}
+	}
+}
+
+func TestLevelFilter(t *testing.T) {
+	tests := []struct {
+		name       string
+		min        Level
+		components map[string]Level
+		component  string
+		level      Level
+		want       bool
+	}{
+		{"below global level", LevelInfo, nil, "", LevelDebug, false},
+		{"at global level", LevelInfo, nil, "", LevelInfo, true},
+		{"component without override uses global", LevelWarn, map[string]Level{"limiter": LevelDebug}, "setting", LevelInfo, false},
+		{"component override lowers level", LevelWarn, map[string]Level{"limiter": LevelDebug}, "limiter", LevelDebug, true},
+		{"component override raises level", LevelDebug, map[string]Level{"limiter": LevelError}, "limiter", LevelWarn, false},
+		{"empty component ignores override", LevelWarn, map[string]Level{"": LevelDebug}, "", LevelInfo, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := NewLogger()
+			l.SetLevel(tt.min)
+			for component, level := range tt.components {
+				l.SetComponentLevel(component, level)
+			}
+			if got := l.WithComponent(tt.component).Enabled(tt.level); got != tt.want {
+				t.Errorf("Enabled(%v) = %v, want %v", tt.level, got, tt.want)
+			}
+		})
+	}
+}
+
+// 派生出的 Logger 共享级别设置，运行时修改对它们立即生效
+func TestLevelFilterShared(t *testing.T) {
+	var buf bytes.Buffer
+	root := NewLogger(NewSink(&buf, FormatJSON))
+	limiter := root.WithFields(Fields{"k": "v"}).WithComponent("limiter")
+
+	root.SetLevel(LevelWarn)
+	limiter.Info("hidden")
+	if buf.Len() != 0 {
+		t.Fatalf("wrote %q below the global level", buf.String())
+	}
+	root.SetComponentLevel("limiter", LevelDebug)
+	limiter.Info("shown")
+	if !strings.Contains(buf.String(), "shown") {
+		t.Fatalf("component override not applied: %q", buf.String())
+	}
+	min, components := root.Levels()
+	if min != LevelWarn || components["limiter"] != LevelDebug {
+		t.Errorf("Levels() = %v, %v", min, components)
+	}
+
+	buf.Reset()
+	limiter.ResetComponentLevel("limiter")
+	limiter.Info("hidden again")
+	if buf.Len() != 0 {
+		t.Errorf("wrote %q after resetting the override", buf.String())
+	}
+}
@@ -0,0 +1,130 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+type Format string
+
+const (
+	// 每行一个 JSON 对象
+	FormatJSON Format = "json"
+	// 便于人工阅读的单行文本
+	FormatConsole Format = "console"
+)
+
+func ParseFormat(s string) (Format, error) {
+	switch Format(s) {
+	case "", FormatJSON:
+		return FormatJSON, nil
+	case FormatConsole:
+		return FormatConsole, nil
+	}
+	return FormatJSON, fmt.Errorf("logger: unknown format %q", s)
+}
+
+// 需要感知日志级别的输出目标（如 syslog）可以实现该接口
+type LevelWriter interface {
+	WriteLevel(level Level, p []byte) (int, error)
+}
+
+// 日志的一个输出目标
+type Sink struct {
+	mu     sync.Mutex
+	w      io.Writer
+	format Format
+}
+
+func NewSink(w io.Writer, format Format) *Sink {
+	return &Sink{w: w, format: format}
+}
+
+func (f Format) encode(level Level, data Fields) []byte {
+	if f == FormatConsole {
+		return consoleFormat(level, data)
+	}
+	return jsonFormat(data)
+}
+
+func (s *Sink) write(level Level, line []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var err error
+	if lw, ok := s.w.(LevelWriter); ok {
+		_, err = lw.WriteLevel(level, line)
+	} else {
+		_, err = s.w.Write(line)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "logger: write sink err: %v\n", err)
+	}
+}
+
+// 如果输出目标带有缓冲（如 AsyncWriter）则等待其写完
+func (s *Sink) Flush() error {
+	if f, ok := s.w.(interface{ Flush() error }); ok {
+		return f.Flush()
+	}
+	return nil
+}
+
+// 如果输出目标实现了 io.Closer 则关闭它
+func (s *Sink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if c, ok := s.w.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+func jsonFormat(data Fields) []byte {
+	body, err := json.Marshal(data)
+	if err != nil {
+		body, _ = json.Marshal(Fields{
+			"level":   data["level"],
+			"time":    data["time"],
+			"message": data["message"],
+			"error":   "logger: marshal fields err: " + err.Error(),
+		})
+	}
+	return append(body, '\n')
+}
+
+// 格式：时间 级别 [组件] 消息 key=value... 调用位置
+func consoleFormat(level Level, data Fields) []byte {
+	var b bytes.Buffer
+	if t, ok := data["time"].(int64); ok {
+		b.WriteString(time.Unix(0, t).Format("2006-01-02 15:04:05.000"))
+	}
+	fmt.Fprintf(&b, " %-5s", level.String())
+	if component, ok := data["component"]; ok {
+		fmt.Fprintf(&b, " [%v]", component)
+	}
+	fmt.Fprintf(&b, " %v", data["message"])
+
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		switch k {
+		case "time", "level", "message", "component", "callers":
+			continue
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(&b, " %s=%v", k, data[k])
+	}
+	if callers, ok := data["callers"].([]string); ok && len(callers) > 0 {
+		fmt.Fprintf(&b, " caller=%s", callers[0])
+	}
+	b.WriteByte('\n')
+	return b.Bytes()
+}
@@ -0,0 +1,109 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Format
+		wantErr bool
+	}{
+		{"", FormatJSON, false},
+		{"json", FormatJSON, false},
+		{"console", FormatConsole, false},
+		{"text", FormatJSON, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseFormat(tt.in)
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("ParseFormat(%q) = %v, %v, want %v, err %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestFormats(t *testing.T) {
+	tests := []struct {
+		format Format
+		check  func(t *testing.T, line string)
+	}{
+		{FormatJSON, func(t *testing.T, line string) {
+			var data Fields
+			if err := json.Unmarshal([]byte(line), &data); err != nil {
+				t.Fatalf("not a JSON line: %v", err)
+			}
+			want := Fields{"level": "warn", "message": "slow query", "component": "dao", "table": "blog_tag"}
+			for k, v := range want {
+				if data[k] != v {
+					t.Errorf("%s = %v, want %v", k, data[k], v)
+				}
+			}
+		}},
+		{FormatConsole, func(t *testing.T, line string) {
+			re := `^\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2}\.\d{3} warn  \[dao\] slow query rows=3 table=blog_tag\n$`
+			if !regexp.MustCompile(re).MatchString(line) {
+				t.Errorf("line = %q, want it to match %s", line, re)
+			}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			var buf bytes.Buffer
+			l := NewLogger(NewSink(&buf, tt.format)).WithComponent("dao")
+			l.WithFields(Fields{"table": "blog_tag", "rows": 3}).Warn("slow query")
+			if strings.Count(buf.String(), "\n") != 1 {
+				t.Fatalf("wrote %q, want exactly one line", buf.String())
+			}
+			tt.check(t, buf.String())
+		})
+	}
+}
+
+// 同一条日志按各自的格式写入每个输出目标
+func TestLoggerFansOutToSinks(t *testing.T) {
+	var json1, json2, console bytes.Buffer
+	l := NewLogger(
+		NewSink(&json1, FormatJSON),
+		NewSink(&console, FormatConsole),
+		NewSink(&json2, FormatJSON),
+	)
+	l.Info("started")
+	l.Debug("listening")
+
+	for name, buf := range map[string]*bytes.Buffer{"json1": &json1, "json2": &json2, "console": &console} {
+		if got := strings.Count(buf.String(), "\n"); got != 2 {
+			t.Errorf("%s got %d lines, want 2: %q", name, got, buf.String())
+		}
+	}
+	if json1.String() != json2.String() {
+		t.Errorf("JSON sinks differ: %q vs %q", json1.String(), json2.String())
+	}
+	if !strings.HasPrefix(json1.String(), "{") || strings.HasPrefix(console.String(), "{") {
+		t.Errorf("formats not applied per sink: json %q, console %q", json1.String(), console.String())
+	}
+}
+
+type levelRecorder struct {
+	bytes.Buffer
+	levels []Level
+}
+
+func (w *levelRecorder) WriteLevel(level Level, p []byte) (int, error) {
+	w.levels = append(w.levels, level)
+	return w.Write(p)
+}
+
+func TestSinkPassesLevel(t *testing.T) {
+	w := &levelRecorder{}
+	l := NewLogger(NewSink(w, FormatConsole))
+	l.Warn("a")
+	l.Log(LevelFatal, "b")
+	if len(w.levels) != 2 || w.levels[0] != LevelWarn || w.levels[1] != LevelFatal {
+		t.Errorf("levels = %v, want [warn fatal]", w.levels)
+	}
+}
@@ -0,0 +1,55 @@
+//go:build !windows && !plan9
+
+package logger
+
+import (
+	"io"
+	"log/syslog"
+)
+
+type syslogWriter struct {
+	w *syslog.Writer
+}
+
+// network 和 addr 为空时连接本机的 syslog 服务
+func NewSyslogWriter(network, addr, tag string) (io.WriteCloser, error) {
+	w, err := syslog.Dial(network, addr, syslog.LOG_INFO|syslog.LOG_USER, tag)
+	if err != nil {
+		return nil, err
+	}
+	return &syslogWriter{w: w}, nil
+}
+
+func (s *syslogWriter) Write(p []byte) (int, error) {
+	return s.w.Write(p)
+}
+
+// 将日志级别映射为 syslog 的严重程度
+func (s *syslogWriter) WriteLevel(level Level, p []byte) (int, error) {
+	m := string(p)
+	var err error
+	switch level {
+	case LevelDebug:
+		err = s.w.Debug(m)
+	case LevelInfo:
+		err = s.w.Info(m)
+	case LevelWarn:
+		err = s.w.Warning(m)
+	case LevelError:
+		err = s.w.Err(m)
+	case LevelFatal:
+		err = s.w.Crit(m)
+	case LevelPanic:
+		err = s.w.Emerg(m)
+	default:
+		err = s.w.Info(m)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (s *syslogWriter) Close() error {
+	return s.w.Close()
+}
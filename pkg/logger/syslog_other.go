@@ -0,0 +1,12 @@
+//go:build windows || plan9
+
+package logger
+
+import (
+	"errors"
+	"io"
+)
+
+func NewSyslogWriter(network, addr, tag string) (io.WriteCloser, error) {
+	return nil, errors.New("logger: syslog is not supported on this platform")
+}
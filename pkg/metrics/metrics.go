@@ -0,0 +1,73 @@
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "blog_service"
+
+// 本服务使用独立的注册表，避免引入的第三方库向默认注册表注册无关指标
+var Registry = prometheus.NewRegistry()
+
+var (
+	HTTPRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "Total number of HTTP requests by route and status.",
+	}, []string{"method", "route", "status"})
+
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by route and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	RateLimitRejectionsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rate_limit_rejections_total",
+		Help:      "Total number of requests rejected by the rate limiter.",
+	}, []string{"method", "route"})
+
+	UploadBytesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "upload_bytes_total",
+		Help:      "Total bytes of successfully uploaded files.",
+	}, []string{"type"})
+)
+
+func init() {
+	Registry.MustRegister(
+		HTTPRequestsTotal,
+		HTTPRequestDuration,
+		RateLimitRejectionsTotal,
+		UploadBytesTotal,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{Namespace: namespace}),
+	)
+}
+
+// 注册数据库连接池指标，数据来自 db.Stats()
+func RegisterDBStats(db *sql.DB, dbName string) error {
+	return Registry.Register(collectors.NewDBStatsCollector(db, dbName))
+}
+
+// 注册异步日志写入器因缓冲区已满而丢弃的日志条数，sink 区分不同的输出目标
+func RegisterLogDropped(sink string, dropped func() uint64) error {
+	return Registry.Register(prometheus.NewCounterFunc(prometheus.CounterOpts{
+		Namespace:   namespace,
+		Name:        "log_dropped_entries_total",
+		Help:        "Total number of log entries dropped by an asynchronous sink because its buffer was full.",
+		ConstLabels: prometheus.Labels{"sink": sink},
+	}, func() float64 { return float64(dropped()) }))
+}
+
+// Prometheus 文本格式的指标输出
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
@@ -0,0 +1,36 @@
+package metrics
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestRegisterLogDropped(t *testing.T) {
+	var dropped uint64 = 3
+	if err := RegisterLogDropped("test-file", func() uint64 { return dropped }); err != nil {
+		t.Fatal(err)
+	}
+	//同一个 sink 不能重复注册
+	if err := RegisterLogDropped("test-file", func() uint64 { return 0 }); err == nil {
+		t.Error("registering the same sink twice did not fail")
+	}
+	dropped = 5
+
+	want := `
+# HELP blog_service_log_dropped_entries_total Total number of log entries dropped by an asynchronous sink because its buffer was full.
+# TYPE blog_service_log_dropped_entries_total counter
+blog_service_log_dropped_entries_total{sink="test-file"} 5
+`
+	if err := testutil.GatherAndCompare(Registry, strings.NewReader(want), "blog_service_log_dropped_entries_total"); err != nil {
+		t.Error(err)
+	}
+
+	w := httptest.NewRecorder()
+	Handler().ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	if !strings.Contains(w.Body.String(), `blog_service_log_dropped_entries_total{sink="test-file"} 5`) {
+		t.Errorf("/metrics does not contain the dropped entries of test-file")
+	}
+}
@@ -0,0 +1,114 @@
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// 校验 exp、iat、nbf 时允许的时钟偏差
+const clockSkew = time.Minute
+
+// IdP 可能把 aud 写成字符串或字符串数组
+type Audience []string
+
+func (a *Audience) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err == nil {
+		*a = Audience{s}
+		return nil
+	}
+	var ss []string
+	if err := json.Unmarshal(b, &ss); err != nil {
+		return err
+	}
+	*a = ss
+	return nil
+}
+
+func (a Audience) Contains(v string) bool {
+	for _, s := range a {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+// ID Token 中本项目关心的声明
+type IDTokenClaims struct {
+	Issuer            string   `json:"iss"`
+	Subject           string   `json:"sub"`
+	Audience          Audience `json:"aud"`
+	AuthorizedParty   string   `json:"azp"`
+	ExpiresAt         int64    `json:"exp"`
+	IssuedAt          int64    `json:"iat"`
+	NotBefore         int64    `json:"nbf"`
+	Nonce             string   `json:"nonce"`
+	Email             string   `json:"email"`
+	EmailVerified     bool     `json:"email_verified"`
+	Name              string   `json:"name"`
+	PreferredUsername string   `json:"preferred_username"`
+}
+
+// 实现 jwt.Claims，由 jwt-go 在验签之后调用
+func (c *IDTokenClaims) Valid() error {
+	now := time.Now()
+	if c.ExpiresAt == 0 || now.After(time.Unix(c.ExpiresAt, 0).Add(clockSkew)) {
+		return errors.New("oidc: id_token is expired")
+	}
+	if c.IssuedAt != 0 && now.Add(clockSkew).Before(time.Unix(c.IssuedAt, 0)) {
+		return errors.New("oidc: id_token used before issued")
+	}
+	if c.NotBefore != 0 && now.Add(clockSkew).Before(time.Unix(c.NotBefore, 0)) {
+		return errors.New("oidc: id_token is not valid yet")
+	}
+	return nil
+}
+
+// 只接受非对称签名算法，防止 alg=none 或使用 HS256 冒充
+var allowedAlgs = map[string]bool{
+	"RS256": true, "RS384": true, "RS512": true,
+	"ES256": true, "ES384": true, "ES512": true,
+}
+
+// 校验 ID Token 的签名、签发者、受众、有效期和 nonce
+func (p *Provider) VerifyIDToken(ctx context.Context, rawIDToken, nonce string) (*IDTokenClaims, error) {
+	metadata, err := p.Metadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+	claims := &IDTokenClaims{}
+	_, err = jwt.ParseWithClaims(rawIDToken, claims, func(t *jwt.Token) (any, error) {
+		if !allowedAlgs[t.Method.Alg()] {
+			return nil, fmt.Errorf("oidc: unexpected signing algorithm %q", t.Method.Alg())
+		}
+		kid, _ := t.Header["kid"].(string)
+		return p.keys.Get(ctx, kid)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSuffix(claims.Issuer, "/") != strings.TrimSuffix(metadata.Issuer, "/") {
+		return nil, fmt.Errorf("oidc: id_token issued by %q, expected %q", claims.Issuer, metadata.Issuer)
+	}
+	if !claims.Audience.Contains(p.config.ClientID) {
+		return nil, errors.New("oidc: id_token audience does not contain client_id")
+	}
+	if len(claims.Audience) > 1 && claims.AuthorizedParty != p.config.ClientID {
+		return nil, errors.New("oidc: id_token azp does not match client_id")
+	}
+	if claims.Subject == "" {
+		return nil, errors.New("oidc: id_token is missing sub")
+	}
+	if claims.Nonce != nonce {
+		return nil, errors.New("oidc: id_token nonce mismatch")
+	}
+	return claims, nil
+}
@@ -0,0 +1,276 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// 本地模拟的 IdP：授权端点直接签发授权码，令牌端点校验 PKCE 后返回 RS256 签名的 ID Token
+type mockIdP struct {
+	*httptest.Server
+	key      *rsa.PrivateKey
+	clientID string
+	subject  string
+
+	mu    sync.Mutex
+	codes map[string]authRequest
+}
+
+type authRequest struct {
+	nonce, challenge, redirectURI string
+}
+
+func newMockIdP(t *testing.T, clientID string) *mockIdP {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	idp := &mockIdP{key: key, clientID: clientID, subject: "user-1", codes: map[string]authRequest{}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", idp.discovery)
+	mux.HandleFunc("/authorize", idp.authorize)
+	mux.HandleFunc("/token", idp.token)
+	mux.HandleFunc("/jwks", idp.jwks)
+	idp.Server = httptest.NewServer(mux)
+	t.Cleanup(idp.Close)
+	return idp
+}
+
+func (idp *mockIdP) discovery(w http.ResponseWriter, r *http.Request) {
+	_ = json.NewEncoder(w).Encode(Metadata{
+		Issuer:                idp.URL,
+		AuthorizationEndpoint: idp.URL + "/authorize",
+		TokenEndpoint:         idp.URL + "/token",
+		JWKSURI:               idp.URL + "/jwks",
+	})
+}
+
+func (idp *mockIdP) authorize(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if q.Get("client_id") != idp.clientID || q.Get("code_challenge_method") != "S256" {
+		http.Error(w, "invalid_request", http.StatusBadRequest)
+		return
+	}
+	code, _ := RandomString(8)
+	idp.mu.Lock()
+	idp.codes[code] = authRequest{nonce: q.Get("nonce"), challenge: q.Get("code_challenge"), redirectURI: q.Get("redirect_uri")}
+	idp.mu.Unlock()
+	redirect, _ := url.Parse(q.Get("redirect_uri"))
+	v := redirect.Query()
+	v.Set("code", code)
+	v.Set("state", q.Get("state"))
+	redirect.RawQuery = v.Encode()
+	http.Redirect(w, r, redirect.String(), http.StatusFound)
+}
+
+func (idp *mockIdP) token(w http.ResponseWriter, r *http.Request) {
+	if user, _, ok := r.BasicAuth(); !ok || user != idp.clientID {
+		http.Error(w, "invalid_client", http.StatusUnauthorized)
+		return
+	}
+	idp.mu.Lock()
+	req, ok := idp.codes[r.PostFormValue("code")]
+	delete(idp.codes, r.PostFormValue("code"))
+	idp.mu.Unlock()
+	if !ok || req.redirectURI != r.PostFormValue("redirect_uri") ||
+		CodeChallengeS256(r.PostFormValue("code_verifier")) != req.challenge {
+		http.Error(w, "invalid_grant", http.StatusBadRequest)
+		return
+	}
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":   idp.URL,
+		"sub":   idp.subject,
+		"aud":   idp.clientID,
+		"exp":   now.Add(time.Minute).Unix(),
+		"iat":   now.Unix(),
+		"nonce": req.nonce,
+		"email": "editor@example.com",
+		"name":  "Editor",
+	})
+	token.Header["kid"] = "k1"
+	idToken, err := token.SignedString(idp.key)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(Token{AccessToken: "at", TokenType: "Bearer", IDToken: idToken})
+}
+
+func (idp *mockIdP) jwks(w http.ResponseWriter, r *http.Request) {
+	pub := idp.key.PublicKey
+	_ = json.NewEncoder(w).Encode(map[string]any{"keys": []jsonWebKey{{
+		Kty: "RSA",
+		Kid: "k1",
+		Use: "sig",
+		Alg: "RS256",
+		N:   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+	}}})
+}
+
+// 按浏览器的方式访问授权地址，返回 IdP 回调时携带的 code 和 state
+func authorize(t *testing.T, authURL string) (code, state string) {
+	t.Helper()
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	resp, err := client.Get(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusFound {
+		t.Fatalf("authorize status = %d", resp.StatusCode)
+	}
+	location, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return location.Query().Get("code"), location.Query().Get("state")
+}
+
+func TestAuthorizationCodeFlow(t *testing.T) {
+	idp := newMockIdP(t, "blog-service")
+	provider := NewProvider(&Config{
+		Issuer:      idp.URL,
+		ClientID:    "blog-service",
+		RedirectURL: "http://127.0.0.1:8000/auth/oidc/callback",
+		HTTPClient:  idp.Client(),
+	})
+	states := NewStateStore(time.Minute, 0)
+	ctx := context.Background()
+
+	state, _ := RandomString(16)
+	nonce, _ := RandomString(16)
+	verifier, _ := NewCodeVerifier()
+	authURL, err := provider.AuthCodeURL(ctx, state, nonce, CodeChallengeS256(verifier))
+	if err != nil {
+		t.Fatalf("AuthCodeURL err: %v", err)
+	}
+	states.Save(state, &AuthSession{Nonce: nonce, CodeVerifier: verifier})
+
+	code, returnedState := authorize(t, authURL)
+	session, ok := states.Take(returnedState)
+	if !ok {
+		t.Fatalf("state %q was not found", returnedState)
+	}
+	if _, ok := states.Take(returnedState); ok {
+		t.Fatal("state can be taken twice")
+	}
+	token, err := provider.Exchange(ctx, code, session.CodeVerifier)
+	if err != nil {
+		t.Fatalf("Exchange err: %v", err)
+	}
+	claims, err := provider.VerifyIDToken(ctx, token.IDToken, session.Nonce)
+	if err != nil {
+		t.Fatalf("VerifyIDToken err: %v", err)
+	}
+	if claims.Subject != "user-1" || claims.Email != "editor@example.com" {
+		t.Errorf("claims = %+v", claims)
+	}
+}
+
+func TestAuthorizationCodeFlowRejects(t *testing.T) {
+	tests := []struct {
+		name            string
+		verifier        func(verifier string) string
+		nonce           func(nonce string) string
+		wantExchangeErr bool
+	}{
+		{
+			name:            "wrong code verifier",
+			verifier:        func(string) string { v, _ := NewCodeVerifier(); return v },
+			nonce:           func(n string) string { return n },
+			wantExchangeErr: true,
+		},
+		{
+			name:     "nonce mismatch",
+			verifier: func(v string) string { return v },
+			nonce:    func(string) string { return "other" },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			idp := newMockIdP(t, "blog-service")
+			provider := NewProvider(&Config{
+				Issuer:      idp.URL,
+				ClientID:    "blog-service",
+				RedirectURL: "http://127.0.0.1:8000/auth/oidc/callback",
+				HTTPClient:  idp.Client(),
+			})
+			ctx := context.Background()
+			nonce, _ := RandomString(16)
+			verifier, _ := NewCodeVerifier()
+			authURL, err := provider.AuthCodeURL(ctx, "state", nonce, CodeChallengeS256(verifier))
+			if err != nil {
+				t.Fatal(err)
+			}
+			code, _ := authorize(t, authURL)
+			token, err := provider.Exchange(ctx, code, tt.verifier(verifier))
+			if tt.wantExchangeErr {
+				if err == nil {
+					t.Fatal("Exchange succeeded, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Exchange err: %v", err)
+			}
+			if _, err := provider.VerifyIDToken(ctx, token.IDToken, tt.nonce(nonce)); err == nil {
+				t.Fatal("flow succeeded, want error")
+			}
+		})
+	}
+}
+
+func TestIssuerMismatch(t *testing.T) {
+	idp := newMockIdP(t, "blog-service")
+	provider := NewProvider(&Config{Issuer: idp.URL + "/other", ClientID: "blog-service", HTTPClient: idp.Client()})
+	// 服务发现地址拼接在 Issuer 之后，/other 下没有文档，同样应失败
+	if _, err := provider.Metadata(context.Background()); err == nil {
+		t.Fatal("Metadata succeeded, want error")
+	}
+}
+
+func TestStateStoreEvictsOldest(t *testing.T) {
+	states := NewStateStore(time.Minute, 2)
+	for _, state := range []string{"a", "b", "c"} {
+		states.Save(state, &AuthSession{Nonce: state})
+	}
+	if n := states.Len(); n != 2 {
+		t.Fatalf("Len() = %d, want 2", n)
+	}
+	if _, ok := states.Take("a"); ok {
+		t.Error("oldest state a was not evicted")
+	}
+	for _, state := range []string{"b", "c"} {
+		if _, ok := states.Take(state); !ok {
+			t.Errorf("state %s was evicted", state)
+		}
+	}
+}
+
+func TestStateStoreExpires(t *testing.T) {
+	states := NewStateStore(-time.Second, 0)
+	states.Save("a", &AuthSession{})
+	if _, ok := states.Take("a"); ok {
+		t.Error("expired state was accepted")
+	}
+	states.Save("b", &AuthSession{})
+	states.Save("c", &AuthSession{})
+	if n := states.Len(); n != 1 {
+		t.Errorf("Len() = %d, want expired states pruned on save", n)
+	}
+}
@@ -0,0 +1,27 @@
+package oidc
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+)
+
+// 生成 URL 安全的随机字符串，用作 state、nonce 和 PKCE verifier
+func RandomString(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// 生成 PKCE code_verifier（RFC 7636 要求 43~128 个字符）
+func NewCodeVerifier() (string, error) {
+	return RandomString(32)
+}
+
+// 根据 code_verifier 计算 S256 方式的 code_challenge
+func CodeChallengeS256(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,86 @@
+package oidc
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// 未指定上限时最多同时保存的登录会话数
+const DefaultMaxStates = 10000
+
+// 一次登录流程中需要在跳转前后保持的数据
+type AuthSession struct {
+	Nonce        string
+	CodeVerifier string
+	ExpiresAt    time.Time
+}
+
+// 以 state 为键保存登录会话，每个 state 只能使用一次。
+// 发起登录不需要认证，因此会话数有上限，超出时淘汰最早的会话
+type StateStore struct {
+	mu       sync.Mutex
+	ttl      time.Duration
+	max      int
+	order    *list.List //按保存顺序排列，所有会话有效期相同，最早保存的最先过期
+	sessions map[string]*list.Element
+}
+
+type stateEntry struct {
+	state   string
+	session *AuthSession
+}
+
+// max 小于等于 0 时使用 DefaultMaxStates
+func NewStateStore(ttl time.Duration, max int) *StateStore {
+	if max <= 0 {
+		max = DefaultMaxStates
+	}
+	return &StateStore{ttl: ttl, max: max, order: list.New(), sessions: map[string]*list.Element{}}
+}
+
+func (s *StateStore) Save(state string, session *AuthSession) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	for e := s.order.Front(); e != nil; e = s.order.Front() {
+		if entry := e.Value.(*stateEntry); now.After(entry.session.ExpiresAt) || s.order.Len() >= s.max {
+			s.remove(e)
+			continue
+		}
+		break
+	}
+	if e, ok := s.sessions[state]; ok {
+		s.remove(e)
+	}
+	session.ExpiresAt = now.Add(s.ttl)
+	s.sessions[state] = s.order.PushBack(&stateEntry{state: state, session: session})
+}
+
+// 取出并删除 state 对应的会话，过期或不存在时返回 false
+func (s *StateStore) Take(state string) (*AuthSession, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.sessions[state]
+	if !ok {
+		return nil, false
+	}
+	s.remove(e)
+	session := e.Value.(*stateEntry).session
+	if time.Now().After(session.ExpiresAt) {
+		return nil, false
+	}
+	return session, true
+}
+
+// 当前保存的会话数，包括尚未清理的过期会话
+func (s *StateStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.order.Len()
+}
+
+func (s *StateStore) remove(e *list.Element) {
+	s.order.Remove(e)
+	delete(s.sessions, e.Value.(*stateEntry).state)
+}
@@ -0,0 +1,211 @@
+package redact
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+const Mask = "******"
+
+// 未配置时默认脱敏的字段、查询参数和请求头。
+// code 在响应中是错误码，因此只作为查询参数（OAuth 回调的授权码）脱敏
+var (
+	DefaultFields = []string{
+		"app_secret", "password", "secret", "token",
+		"access_token", "refresh_token", "id_token", "code_verifier",
+	}
+	DefaultQueryParams = []string{"code"}
+	DefaultHeaders     = []string{"Authorization", "Cookie", "Set-Cookie", "Token"}
+)
+
+type Rules struct {
+	// 字段名（不区分大小写），在表单、JSON 的任意层级中出现都会被脱敏
+	Fields []string
+	// 只在 URL 查询字符串中脱敏的参数名（不区分大小写），如 OAuth 回调中的 code
+	QueryParams []string
+	// JSON 路径，如 data.token、list.*.app_secret，* 匹配任意键或数组元素
+	JSONPaths []string
+	// 请求头名称
+	Headers []string
+	// 正则表达式，匹配到的内容会被替换为掩码
+	Patterns []string
+}
+
+type Redactor struct {
+	fields    map[string]bool
+	query     map[string]bool
+	paths     [][]string
+	headers   map[string]bool
+	patterns  []*regexp.Regexp
+	fieldExpr *regexp.Regexp
+}
+
+// 无效的正则会被忽略并通过 error 返回，返回的 Redactor 始终可用
+func New(rules Rules) (*Redactor, error) {
+	if len(rules.Fields) == 0 {
+		rules.Fields = DefaultFields
+	}
+	if len(rules.QueryParams) == 0 {
+		rules.QueryParams = DefaultQueryParams
+	}
+	if len(rules.Headers) == 0 {
+		rules.Headers = DefaultHeaders
+	}
+	r := &Redactor{
+		fields:  make(map[string]bool, len(rules.Fields)),
+		query:   make(map[string]bool, len(rules.QueryParams)),
+		headers: make(map[string]bool, len(rules.Headers)),
+	}
+	for _, q := range rules.QueryParams {
+		r.query[strings.ToLower(q)] = true
+	}
+	quoted := make([]string, 0, len(rules.Fields))
+	for _, f := range rules.Fields {
+		r.fields[strings.ToLower(f)] = true
+		quoted = append(quoted, regexp.QuoteMeta(f))
+	}
+	for _, h := range rules.Headers {
+		r.headers[http.CanonicalHeaderKey(h)] = true
+	}
+	for _, p := range rules.JSONPaths {
+		r.paths = append(r.paths, strings.Split(strings.TrimPrefix(p, "$."), "."))
+	}
+	// 用于处理被截断、无法解析的内容：匹配 "key":"value" 和 key=value 两种形式
+	r.fieldExpr = regexp.MustCompile(`(?i)("(?:` + strings.Join(quoted, "|") + `)"\s*:\s*)"(?:[^"\\]|\\.)*"?|\b((?:` + strings.Join(quoted, "|") + `)=)[^&\s]*`)
+
+	var errs []error
+	for _, p := range rules.Patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("redact: invalid pattern %q: %w", p, err))
+			continue
+		}
+		r.patterns = append(r.patterns, re)
+	}
+	return r, errors.Join(errs...)
+}
+
+// 返回脱敏后的表单副本
+func (r *Redactor) Values(values url.Values) url.Values {
+	out := make(url.Values, len(values))
+	for k, vs := range values {
+		if r.fields[strings.ToLower(k)] {
+			out[k] = []string{Mask}
+			continue
+		}
+		masked := make([]string, len(vs))
+		for i, v := range vs {
+			masked[i] = r.Text(v)
+		}
+		out[k] = masked
+	}
+	return out
+}
+
+// 返回脱敏后的查询参数副本，在 Values 的基础上再屏蔽 QueryParams 中的参数
+func (r *Redactor) Query(values url.Values) url.Values {
+	out := r.Values(values)
+	for k := range out {
+		if r.query[strings.ToLower(k)] {
+			out[k] = []string{Mask}
+		}
+	}
+	return out
+}
+
+// 返回脱敏后的请求头，多个值以逗号拼接
+func (r *Redactor) Header(header http.Header) map[string]string {
+	out := make(map[string]string, len(header))
+	for k, vs := range header {
+		if r.headers[http.CanonicalHeaderKey(k)] {
+			out[k] = Mask
+			continue
+		}
+		out[k] = r.Text(strings.Join(vs, ","))
+	}
+	return out
+}
+
+// 解析 JSON 并按字段名、JSON 路径和正则脱敏；无法解析时退化为 Text
+func (r *Redactor) JSON(body []byte) string {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return r.Text(string(body))
+	}
+	v = r.walk(v, nil)
+	out, err := json.Marshal(v)
+	if err != nil {
+		return r.Text(string(body))
+	}
+	return string(out)
+}
+
+// 对纯文本按字段名和正则脱敏
+func (r *Redactor) Text(s string) string {
+	s = r.fieldExpr.ReplaceAllStringFunc(s, func(m string) string {
+		sub := r.fieldExpr.FindStringSubmatch(m)
+		if sub[1] != "" {
+			return sub[1] + `"` + Mask + `"`
+		}
+		return sub[2] + Mask
+	})
+	for _, re := range r.patterns {
+		s = re.ReplaceAllString(s, Mask)
+	}
+	return s
+}
+
+func (r *Redactor) walk(v any, path []string) any {
+	switch t := v.(type) {
+	case map[string]any:
+		for k, child := range t {
+			childPath := append(path[:len(path):len(path)], k)
+			if r.fields[strings.ToLower(k)] || r.matchPath(childPath) {
+				t[k] = Mask
+				continue
+			}
+			t[k] = r.walk(child, childPath)
+		}
+		return t
+	case []any:
+		for i, child := range t {
+			childPath := append(path[:len(path):len(path)], "*")
+			if r.matchPath(childPath) {
+				t[i] = Mask
+				continue
+			}
+			t[i] = r.walk(child, childPath)
+		}
+		return t
+	case string:
+		return r.Text(t)
+	}
+	return v
+}
+
+func (r *Redactor) matchPath(path []string) bool {
+	for _, p := range r.paths {
+		if len(p) != len(path) {
+			continue
+		}
+		matched := true
+		for i := range p {
+			if p[i] != "*" && p[i] != path[i] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,159 @@
+package redact
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestErrorCodeIsNotMasked(t *testing.T) {
+	r, err := New(Rules{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := r.JSON([]byte(`{"code":20010001,"msg":"入参错误","token":"abc"}`))
+	if !strings.Contains(got, `"code":20010001`) {
+		t.Errorf("error code was masked: %s", got)
+	}
+	if strings.Contains(got, "abc") {
+		t.Errorf("token was not masked: %s", got)
+	}
+}
+
+func TestQueryMasksQueryParams(t *testing.T) {
+	r, err := New(Rules{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := url.Values{"code": {"auth-code"}, "state": {"s1"}, "password": {"p"}}
+	got := r.Query(values)
+	if got.Get("code") != Mask || got.Get("password") != Mask {
+		t.Errorf("Query() = %v, want code and password masked", got)
+	}
+	if got.Get("state") != "s1" {
+		t.Errorf("state = %q, want s1", got.Get("state"))
+	}
+	// Values 不处理只针对查询字符串的参数，也不修改原始值
+	if r.Values(values).Get("code") != "auth-code" || values.Get("code") != "auth-code" {
+		t.Error("code was masked outside of the query string")
+	}
+}
+
+func TestJSONPaths(t *testing.T) {
+	r, err := New(Rules{JSONPaths: []string{"data.card", "$.list.*.phone", "items.*"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		body    string
+		masked  []string
+		visible []string
+	}{
+		{"nested key", `{"data":{"card":"4111","name":"go"}}`, []string{"4111"}, []string{"go"}},
+		{"array wildcard", `{"list":[{"phone":"138"},{"phone":"139","id":7}]}`, []string{"138", "139"}, []string{`"id":7`}},
+		{"whole array elements", `{"items":["a1","b2"]}`, []string{"a1", "b2"}, nil},
+		{"same key elsewhere", `{"card":"5500","data":{"card":"4111"}}`, []string{"4111"}, []string{"5500"}},
+		{"default fields still apply", `{"data":{"password":"p@ss"}}`, []string{"p@ss"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := r.JSON([]byte(tt.body))
+			for _, s := range tt.masked {
+				if strings.Contains(got, s) {
+					t.Errorf("JSON() = %s, want %q masked", got, s)
+				}
+			}
+			for _, s := range tt.visible {
+				if !strings.Contains(got, s) {
+					t.Errorf("JSON() = %s, want %q kept", got, s)
+				}
+			}
+		})
+	}
+}
+
+func TestHeader(t *testing.T) {
+	tests := []struct {
+		name    string
+		rules   Rules
+		header  http.Header
+		masked  []string
+		visible map[string]string
+	}{
+		{
+			name:    "default headers",
+			header:  http.Header{"Authorization": {"Bearer t"}, "Cookie": {"sid=1"}, "Token": {"jwt"}, "Accept": {"text/html"}},
+			masked:  []string{"Authorization", "Cookie", "Token"},
+			visible: map[string]string{"Accept": "text/html"},
+		},
+		{
+			name:    "configured headers replace defaults",
+			rules:   Rules{Headers: []string{"x-api-key"}},
+			header:  http.Header{"X-Api-Key": {"k"}, "Authorization": {"Bearer t"}},
+			masked:  []string{"X-Api-Key"},
+			visible: map[string]string{"Authorization": "Bearer t"},
+		},
+		{
+			name:    "values joined and field rules applied",
+			header:  http.Header{"X-Forwarded-Query": {"a=1", "token=abc"}},
+			visible: map[string]string{"X-Forwarded-Query": "a=1,token=" + Mask},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := New(tt.rules)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := r.Header(tt.header)
+			for _, k := range tt.masked {
+				if got[k] != Mask {
+					t.Errorf("%s = %q, want masked", k, got[k])
+				}
+			}
+			for k, v := range tt.visible {
+				if got[k] != v {
+					t.Errorf("%s = %q, want %q", k, got[k], v)
+				}
+			}
+		})
+	}
+}
+
+// 截断的内容无法按 JSON 解析，按文本规则仍能屏蔽字段和正则匹配的内容
+func TestTextTruncated(t *testing.T) {
+	r, err := New(Rules{Patterns: []string{`\d{3}-\d{4}`}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name   string
+		body   string
+		secret string
+	}{
+		{"value cut off", `{"user":"go","password":"hunter`, "hunter"},
+		{"escaped quotes", `{"token":"a\"b\"c`, `a\"b`},
+		{"form value", `name=go&app_secret=s3cr`, "s3cr"},
+		{"pattern", `{"phone":"555-1234","no`, "555-1234"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := r.JSON([]byte(tt.body))
+			if strings.Contains(got, tt.secret) || !strings.Contains(got, Mask) {
+				t.Errorf("JSON(%q) = %q, want %q masked", tt.body, got, tt.secret)
+			}
+		})
+	}
+}
+
+func TestInvalidPattern(t *testing.T) {
+	r, err := New(Rules{Patterns: []string{`(`, `\d+`}})
+	if err == nil {
+		t.Error("New() err = nil for an invalid pattern")
+	}
+	if got := r.Text("id 42"); got != "id "+Mask {
+		t.Errorf("Text() = %q, want the valid pattern applied", got)
+	}
+}
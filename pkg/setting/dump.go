@@ -0,0 +1,70 @@
+package setting
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+// 屏蔽 secret 字段时使用的占位符，未配置的字段保持为空，便于看出是否已设置
+const SecretMask = "******"
+
+// 以 YAML 输出生效的配置，sections 为各区段组成的结构体，字段名即区段名；
+// 时长写成 60s 这样的形式，带 secret 标签的字段被屏蔽
+func Dump(w io.Writer, sections any) error {
+	out, err := yaml.Marshal(dumpValue(reflect.ValueOf(sections)))
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(out)
+	return err
+}
+
+func dumpValue(v reflect.Value) any {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if v.Type() == durationType {
+		return time.Duration(v.Int()).String()
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		m := make(yaml.MapSlice, 0, v.NumField())
+		for i := 0; i < v.NumField(); i++ {
+			f := v.Type().Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			var value any
+			if f.Tag.Get("secret") == "true" && !v.Field(i).IsZero() {
+				value = SecretMask
+			} else {
+				value = dumpValue(v.Field(i))
+			}
+			m = append(m, yaml.MapItem{Key: f.Name, Value: value})
+		}
+		return m
+	case reflect.Map:
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j]) })
+		m := make(yaml.MapSlice, 0, len(keys))
+		for _, k := range keys {
+			m = append(m, yaml.MapItem{Key: fmt.Sprint(k), Value: dumpValue(v.MapIndex(k))})
+		}
+		return m
+	case reflect.Slice, reflect.Array:
+		list := make([]any, v.Len())
+		for i := range list {
+			list[i] = dumpValue(v.Index(i))
+		}
+		return list
+	}
+	return v.Interface()
+}
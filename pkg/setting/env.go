@@ -0,0 +1,148 @@
+package setting
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// 环境变量前缀，如 Database.Password 对应 BLOG_DATABASE_PASSWORD
+const EnvPrefix = "BLOG"
+
+// 设置优先级最高的值，用于命令行参数，如 Override("Server.HttpPort", "8080")；需在读取区段前调用
+func (s *Setting) Override(key, value string) {
+	s.overrides[strings.ToLower(key)] = value
+}
+
+// 按区段结构体的字段在配置文件的值上叠加覆盖，优先级从高到低：
+// Override > 环境变量 > 环境变量 _FILE 指向的文件 > 配置文件。
+// 配置文件中没有的键也可以覆盖；普通列表用逗号分隔，对象列表（如 RateLimit.Rules）不支持覆盖；
+// map 字段按前缀匹配，如 BLOG_LOG_LEVELS_LIMITER 对应 Log.Levels 中的 limiter。
+func (s *Setting) applyOverrides(key string, raw any, t reflect.Type) (any, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		m := toStringMap(raw)
+		changed := false
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			name := strings.ToLower(f.Name)
+			current, ok := lookupFold(m, name)
+			v, err := s.applyOverrides(key+"."+f.Name, current, f.Type)
+			if err != nil {
+				return nil, err
+			}
+			if v != nil && (!ok || !reflect.DeepEqual(v, current)) {
+				deleteFold(m, name)
+				m[name] = v
+				changed = true
+			}
+		}
+		if !changed && raw == nil {
+			return nil, nil
+		}
+		return m, nil
+	case reflect.Map:
+		prefix := EnvName(EnvPrefix, key) + "_"
+		var m map[string]any
+		for _, env := range os.Environ() {
+			name, value, _ := strings.Cut(env, "=")
+			if !strings.HasPrefix(name, prefix) || len(name) == len(prefix) {
+				continue
+			}
+			if m == nil {
+				m = toStringMap(raw)
+			}
+			k := strings.ToLower(name[len(prefix):])
+			deleteFold(m, k)
+			m[k] = value
+		}
+		if m == nil {
+			return raw, nil
+		}
+		return m, nil
+	case reflect.Slice, reflect.Array:
+		if elem := t.Elem(); elem.Kind() == reflect.Struct || elem.Kind() == reflect.Map {
+			return raw, nil
+		}
+	}
+
+	value, ok, err := s.lookup(key)
+	if err != nil || !ok {
+		return raw, err
+	}
+	if t.Kind() == reflect.Slice {
+		items := []any{}
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		return items, nil
+	}
+	return value, nil
+}
+
+// 依次查找 Override、环境变量和 _FILE 文件
+func (s *Setting) lookup(key string) (string, bool, error) {
+	if v, ok := s.overrides[strings.ToLower(key)]; ok {
+		return v, true, nil
+	}
+	name := EnvName(EnvPrefix, key)
+	if v, ok := os.LookupEnv(name); ok {
+		return v, true, nil
+	}
+	file, ok := os.LookupEnv(name + "_FILE")
+	if !ok {
+		return "", false, nil
+	}
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return "", false, fmt.Errorf("read %s_FILE: %w", name, err)
+	}
+	return strings.TrimRight(string(b), "\r\n"), true, nil
+}
+
+func EnvName(prefix, key string) string {
+	return prefix + "_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// 复制一份配置，YAML 中的对象可能被解析为 map[any]any
+func toStringMap(raw any) map[string]any {
+	m := map[string]any{}
+	switch raw := raw.(type) {
+	case map[string]any:
+		for k, v := range raw {
+			m[k] = v
+		}
+	case map[any]any:
+		for k, v := range raw {
+			m[fmt.Sprint(k)] = v
+		}
+	}
+	return m
+}
+
+// mapstructure 按字段名匹配时不区分大小写，覆盖时也按不区分大小写查找和删除
+func lookupFold(m map[string]any, name string) (any, bool) {
+	for k, v := range m {
+		if strings.EqualFold(k, name) {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+func deleteFold(m map[string]any, name string) {
+	for k := range m {
+		if strings.EqualFold(k, name) {
+			delete(m, k)
+		}
+	}
+}
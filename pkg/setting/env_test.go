@@ -0,0 +1,136 @@
+package setting
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+const testConfig = `
+Server:
+  RunMode: debug
+  HttpPort: 8000
+  ReadTimeout: 60
+Log:
+  Levels:
+    limiter: info
+`
+
+func newTestSetting(t *testing.T) *Setting {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(file, []byte(testConfig), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	s, err := NewSetting(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func writeSecret(t *testing.T, value string) string {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "secret")
+	if err := os.WriteFile(file, []byte(value+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func TestOverridePrecedence(t *testing.T) {
+	tests := []struct {
+		name     string
+		override string
+		env      string
+		file     string
+		want     string
+	}{
+		{name: "yaml", want: "8000"},
+		{name: "file over yaml", file: "8001", want: "8001"},
+		{name: "env over file", env: "8002", file: "8001", want: "8002"},
+		{name: "flag over env", override: "8003", env: "8002", file: "8001", want: "8003"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestSetting(t)
+			if tt.override != "" {
+				s.Override("Server.HttpPort", tt.override)
+			}
+			if tt.env != "" {
+				t.Setenv("BLOG_SERVER_HTTPPORT", tt.env)
+			}
+			if tt.file != "" {
+				t.Setenv("BLOG_SERVER_HTTPPORT_FILE", writeSecret(t, tt.file))
+			}
+			var server *ServerSettingS
+			if err := s.ReadSection("Server", &server); err != nil {
+				t.Fatal(err)
+			}
+			if server.HttpPort != tt.want {
+				t.Errorf("HttpPort = %q, want %q", server.HttpPort, tt.want)
+			}
+			//未覆盖的键保留配置文件中的值
+			if server.RunMode != "debug" || server.ReadTimeout != time.Minute {
+				t.Errorf("RunMode = %q, ReadTimeout = %v; want values from the config file", server.RunMode, server.ReadTimeout)
+			}
+		})
+	}
+}
+
+func TestOverrideKeysMissingFromConfig(t *testing.T) {
+	s := newTestSetting(t)
+	t.Setenv("BLOG_SERVER_WRITETIMEOUT", "30")
+	t.Setenv("BLOG_SERVER_TRUSTEDPROXIES", "10.0.0.1, 10.0.0.0/8")
+	t.Setenv("BLOG_EMAIL_HOST", "smtp.example.com")
+	t.Setenv("BLOG_EMAIL_PORT", "465")
+	t.Setenv("BLOG_LOG_LEVELS_ORM", "warn")
+
+	var server *ServerSettingS
+	if err := s.ReadSection("Server", &server); err != nil {
+		t.Fatal(err)
+	}
+	if server.WriteTimeout != 30*time.Second {
+		t.Errorf("WriteTimeout = %v, want 30s", server.WriteTimeout)
+	}
+	if want := []string{"10.0.0.1", "10.0.0.0/8"}; !reflect.DeepEqual(server.TrustedProxies, want) {
+		t.Errorf("TrustedProxies = %q, want %q", server.TrustedProxies, want)
+	}
+
+	//配置文件中没有 Email 区段
+	var email *EmailSettingS
+	if err := s.ReadSection("Email", &email); err != nil {
+		t.Fatal(err)
+	}
+	if email == nil || email.Host != "smtp.example.com" || email.Port != 465 {
+		t.Errorf("Email = %+v, want host and port from the environment", email)
+	}
+
+	var log *LogSettingS
+	if err := s.ReadSection("Log", &log); err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]string{"limiter": "info", "orm": "warn"}; !reflect.DeepEqual(log.Levels, want) {
+		t.Errorf("Levels = %v, want %v", log.Levels, want)
+	}
+}
+
+func TestOverrideIsValidated(t *testing.T) {
+	s := newTestSetting(t)
+	s.Override("Server.RunMode", "prod")
+	var server *ServerSettingS
+	if err := s.ReadSection("Server", &server); err == nil {
+		t.Error("invalid -mode value passed validation")
+	}
+}
+
+func TestOverrideFileError(t *testing.T) {
+	s := newTestSetting(t)
+	t.Setenv("BLOG_SERVER_HTTPPORT_FILE", filepath.Join(t.TempDir(), "missing"))
+	var server *ServerSettingS
+	if err := s.ReadSection("Server", &server); err == nil {
+		t.Error("missing _FILE did not return an error")
+	}
+}
@@ -1,60 +1,186 @@
 package setting
 
-import "time"
+import (
+	"fmt"
+	"reflect"
+	"time"
 
-//用于声明配置属性的结构体，并编写读取区段配置的配置方法
+	"github.com/mitchellh/mapstructure"
+)
+
+// 用于声明配置属性的结构体，并编写读取区段配置的配置方法
+// 时长字段可以写成 60s、500ms 这样的字符串，写纯数字时按 unit 标签的单位换算；
+// validate 标签在读取区段时校验，secret 标签的字段在输出配置时会被屏蔽
 type ServerSettingS struct {
-	RunMode      string
-	HttpPort     string
-	ReadTimeout  time.Duration
-	WriteTimeout time.Duration
+	RunMode        string        `validate:"oneof=debug release test"`
+	HttpPort       string        `validate:"required,numeric"`
+	ReadTimeout    time.Duration `unit:"s" validate:"gte=0"`
+	WriteTimeout   time.Duration `unit:"s" validate:"gte=0"`
+	TrustedProxies []string      `validate:"dive,ip|cidr"`
 }
 
 type AppSettingS struct {
-	DefaultPageSize      int
-	MaxPageSize          int
-	LogSavePath          string
-	LogFileName          string
+	DefaultPageSize      int    `validate:"gte=1"`
+	MaxPageSize          int    `validate:"gtefield=DefaultPageSize"`
+	LogSavePath          string `validate:"required"`
+	LogFileName          string `validate:"required"`
 	LogFileExt           string
-	UploadSavePath       string
-	UploadServerUrl      string
-	UploadImageMaxSize   int
+	UploadSavePath       string `validate:"required"`
+	UploadServerUrl      string `validate:"required,url"`
+	UploadImageMaxSize   int    `validate:"gte=1"`
 	UploadImageAllowExts []string
+	ErrorFormat          string `validate:"omitempty,oneof=legacy problem"`
+	ProblemTypeBaseUrl   string
 }
 
 type DatabaseSettingS struct {
-	DBType       string
-	Username     string
-	Password     string
-	Host         string
-	DBName       string
+	DBType       string `validate:"required,oneof=mysql"`
+	Username     string `validate:"required"`
+	Password     string `secret:"true"`
+	Host         string `validate:"required"`
+	DBName       string `validate:"required"`
 	TablePrefix  string
-	Charset      string
+	Charset      string `validate:"required"`
 	ParseTime    bool
-	MaxIdleConns int
-	MaxOpenConns int
+	MaxIdleConns int `validate:"gte=0"`
+	MaxOpenConns int `validate:"gte=0"`
 }
 
+// 读取区段并按 validate 标签校验，校验失败时返回所有不合法的字段
 func (s *Setting) ReadSection(k string, v any) error {
-	err := s.vp.UnmarshalKey(k, v)
+	raw, err := s.applyOverrides(k, s.vp.Get(k), reflect.TypeOf(v))
+	if err != nil {
+		return fmt.Errorf("%s: %w", k, err)
+	}
+	//配置文件中缺少的区段按零值解码，交由校验报告其中的必填项，避免区段指针为 nil
+	if raw == nil {
+		raw = map[string]any{}
+	}
+	raw = withUnits(raw, reflect.TypeOf(v))
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		Result:           v,
+		WeaklyTypedInput: true,
+		DecodeHook: mapstructure.ComposeDecodeHookFunc(
+			mapstructure.StringToTimeDurationHookFunc(),
+			mapstructure.StringToSliceHookFunc(","),
+		),
+	})
 	if err != nil {
 		return err
 	}
-	return nil
+	if err := decoder.Decode(raw); err != nil {
+		return fmt.Errorf("%s: %w", k, err)
+	}
+	return Validate(k, v)
 }
 
 type EmailSettingS struct {
 	Host     string
-	Port     int
+	Port     int `validate:"gte=0,lte=65535"`
 	UserName string
-	Password string
+	Password string `secret:"true"`
 	IsSSL    bool
-	From     string
-	To       []string
+	From     string   `validate:"omitempty,email"`
+	To       []string `validate:"dive,email"`
 }
 
 type JWTSettingS struct {
-	Secret string
-	Issuer string
-	Expire time.Duration
-} 
\ No newline at end of file
+	Secret string        `secret:"true" validate:"required"`
+	Issuer string        `validate:"required"`
+	Expire time.Duration `unit:"s" validate:"min=1s"`
+	// 可以访问 /admin 接口的 app_key，为空时拒绝所有请求
+	AdminAppKeys []string
+}
+
+type OIDCSettingS struct {
+	Enable       bool
+	Issuer       string `validate:"required_if=Enable true,omitempty,url"`
+	ClientID     string `validate:"required_if=Enable true"`
+	ClientSecret string `secret:"true"`
+	RedirectURL  string `validate:"required_if=Enable true,omitempty,url"`
+	Scopes       []string
+	StateExpire  time.Duration `unit:"s" validate:"gte=0"`
+	MaxStates    int           `validate:"gte=0"`
+	AutoCreate   bool
+}
+
+type RateLimitSettingS struct {
+	KeyBy      []string         `validate:"dive,oneof=ip app_key"`
+	MaxBuckets int              `validate:"gte=0"`
+	BucketTTL  time.Duration    `unit:"s" validate:"gte=0"`
+	Rules      []RateLimitRuleS `validate:"dive"`
+	Default    RateLimitRuleS
+	Store      RateLimitStoreS
+}
+
+type RateLimitRuleS struct {
+	Method       string `validate:"omitempty,oneof=* GET POST PUT PATCH DELETE HEAD OPTIONS"`
+	Path         string
+	FillInterval time.Duration `unit:"s" validate:"gte=0"`
+	Capacity     int64         `validate:"gte=0"`
+	Quantum      int64         `validate:"gte=0"`
+}
+
+type RateLimitStoreS struct {
+	Type     string `validate:"omitempty,oneof=local redis"`
+	Addr     string `validate:"required_if=Type redis"`
+	Password string `secret:"true"`
+	DB       int    `validate:"gte=0"`
+	Prefix   string
+	Timeout  time.Duration `unit:"ms" validate:"gte=0"`
+}
+
+type LogSettingS struct {
+	Level  string            `validate:"omitempty,oneof=debug info warn error fatal panic"`
+	Levels map[string]string `validate:"dive,oneof=debug info warn error fatal panic"`
+	Sinks  []LogSinkS        `validate:"dive"`
+}
+
+type LogSinkS struct {
+	Type       string `validate:"omitempty,oneof=file stdout stderr syslog"`
+	Format     string `validate:"omitempty,oneof=json console"`
+	Filename   string
+	MaxSize    int `validate:"gte=0"`
+	MaxAge     int `validate:"gte=0"`
+	MaxBackups int `validate:"gte=0"`
+	Compress   bool
+	Network    string
+	Address    string
+	Tag        string
+	Async      bool
+	BufferSize int    `validate:"gte=0"`
+	BatchSize  int    `validate:"gte=0"`
+	Overflow   string `validate:"omitempty,oneof=block drop"`
+}
+
+type AccessLogSettingS struct {
+	RedactFields      []string
+	RedactQueryParams []string
+	RedactJSONPaths   []string
+	RedactHeaders     []string
+	RedactPatterns    []string `validate:"dive,regexp"`
+	MaxBodySize       int      `validate:"gte=0"`
+	BodyContentTypes  []string
+	Format            string `validate:"omitempty,oneof=json combined"`
+	Sink              LogSinkS
+}
+
+type MetricsSettingS struct {
+	Enable bool
+	Path   string `validate:"required_if=Enable true,omitempty,startswith=/"`
+}
+
+type TracingSettingS struct {
+	Enable      bool
+	ServiceName string `validate:"required_if=Enable true"`
+	Exporter    string `validate:"omitempty,oneof=otlp stdout"`
+	Endpoint    string
+	Insecure    bool
+	SampleRatio float64 `validate:"gte=0,lte=1"`
+}
+
+type HealthSettingS struct {
+	CheckTimeout  time.Duration `unit:"ms" validate:"gte=0"`
+	CheckSMTP     bool
+	ShutdownDelay time.Duration `unit:"s" validate:"gte=0"`
+}
@@ -1,21 +1,37 @@
 package setting
 
-import "github.com/spf13/viper"
+import (
+	"path/filepath"
+
+	"github.com/spf13/viper"
+)
 
 //对读取配置的行为进行封装，以便应用程序的使用
 
 type Setting struct{
-	vp *viper.Viper
+	vp        *viper.Viper
+	watcher   *watcher
+	overrides map[string]string
 }
 //初始化本项目的基础属性
-func NewSetting() (*Setting, error){
+//configs 可以是配置文件所在的目录，也可以是配置文件本身；环境变量在读取区段时覆盖，见 applyOverrides
+func NewSetting(configs ...string) (*Setting, error){
 	vp := viper.New()
 	vp.SetConfigName("config")
-	vp.AddConfigPath("configs/")
+	if len(configs) == 0 {
+		configs = []string{"configs/"}
+	}
+	for _, config := range configs {
+		if ext := filepath.Ext(config); ext == ".yaml" || ext == ".yml" {
+			vp.SetConfigFile(config)
+			continue
+		}
+		vp.AddConfigPath(config)
+	}
 	vp.SetConfigType("yaml")
 	err := vp.ReadInConfig()
 	if err != nil {
 		return nil,err
 	}
-	return &Setting{vp},nil
-}
\ No newline at end of file
+	return &Setting{vp: vp, watcher: &watcher{}, overrides: map[string]string{}}, nil
+}
@@ -0,0 +1,170 @@
+package setting
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+var validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New()
+	//正则在启动和热加载时就编译一次，写错的表达式直接报错而不是在使用时被忽略
+	_ = v.RegisterValidation("regexp", func(fl validator.FieldLevel) bool {
+		_, err := regexp.Compile(fl.Field().String())
+		return err == nil
+	})
+	return v
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// 按 validate 标签校验区段，返回的错误列出所有不合法的字段，如 JWT.Secret is required
+func Validate(section string, v any) error {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil
+	}
+	err := validate.Struct(rv.Interface())
+	var errs validator.ValidationErrors
+	if !errors.As(err, &errs) {
+		return err
+	}
+	msgs := make([]string, 0, len(errs))
+	for _, e := range errs {
+		field := e.StructNamespace()
+		if i := strings.IndexByte(field, '.'); i >= 0 {
+			field = section + field[i:]
+		}
+		msgs = append(msgs, field+" "+describe(e))
+	}
+	return fmt.Errorf("invalid %s setting: %s", section, strings.Join(msgs, "; "))
+}
+
+func describe(e validator.FieldError) string {
+	got := fmt.Sprintf("%v", e.Value())
+	if s, ok := e.Value().(string); ok {
+		got = strconv.Quote(s)
+	}
+	switch e.Tag() {
+	case "required":
+		return "is required"
+	case "required_if":
+		//参数形如 "Enable true"
+		f := strings.Fields(e.Param())
+		var conds []string
+		for i := 0; i+1 < len(f); i += 2 {
+			conds = append(conds, f[i]+" is "+f[i+1])
+		}
+		return "is required when " + strings.Join(conds, " and ")
+	case "oneof":
+		return fmt.Sprintf("must be one of [%s], got %s", e.Param(), got)
+	case "min", "gte":
+		return fmt.Sprintf("must be at least %s, got %s", e.Param(), got)
+	case "max", "lte":
+		return fmt.Sprintf("must be at most %s, got %s", e.Param(), got)
+	case "gtefield":
+		return fmt.Sprintf("must not be less than %s, got %s", e.Param(), got)
+	case "regexp":
+		_, err := regexp.Compile(fmt.Sprint(e.Value()))
+		return fmt.Sprintf("must be a valid regular expression, got %s: %v", got, err)
+	}
+	tag := e.Tag()
+	if e.Param() != "" {
+		tag += "=" + e.Param()
+	}
+	return fmt.Sprintf("must satisfy %s, got %s", tag, got)
+}
+
+// 把时长字段中的纯数字按 unit 标签换算为 60s 这样的字符串，交给解码时的 StringToTimeDurationHookFunc 解析；
+// 返回新的值，不修改 viper 中的配置
+func withUnits(raw any, t reflect.Type) any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		var m map[string]any
+		switch raw := raw.(type) {
+		case map[string]any:
+			m = make(map[string]any, len(raw))
+			for k, v := range raw {
+				m[k] = v
+			}
+		case map[any]any:
+			m = make(map[string]any, len(raw))
+			for k, v := range raw {
+				m[fmt.Sprint(k)] = v
+			}
+		default:
+			return raw
+		}
+		for k, v := range m {
+			//mapstructure 按字段名匹配，不区分大小写
+			f, ok := t.FieldByNameFunc(func(name string) bool { return strings.EqualFold(name, k) })
+			if !ok {
+				continue
+			}
+			if f.Type == durationType {
+				m[k] = withUnit(v, f.Tag.Get("unit"))
+				continue
+			}
+			m[k] = withUnits(v, f.Type)
+		}
+		return m
+	case reflect.Slice:
+		list, ok := raw.([]any)
+		if !ok {
+			return raw
+		}
+		out := make([]any, len(list))
+		for i, v := range list {
+			out[i] = withUnits(v, t.Elem())
+		}
+		return out
+	}
+	return raw
+}
+
+func withUnit(v any, unit string) any {
+	base := time.Duration(1)
+	switch unit {
+	case "s":
+		base = time.Second
+	case "ms":
+		base = time.Millisecond
+	}
+	var n float64
+	switch v := v.(type) {
+	case int:
+		n = float64(v)
+	case int64:
+		n = float64(v)
+	case uint64:
+		n = float64(v)
+	case float64:
+		n = v
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return v
+		}
+		n = f
+	default:
+		return v
+	}
+	return time.Duration(n * float64(base)).String()
+}
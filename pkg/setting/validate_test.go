@@ -0,0 +1,171 @@
+package setting
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidateRedactPatterns(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		wantErr  string
+	}{
+		{"valid", []string{`\d{11}`, `(?i)bearer\s+\S+`}, ""},
+		{"invalid", []string{`\d{11}`, `(unclosed`}, "AccessLog.RedactPatterns[1] must be a valid regular expression"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate("AccessLog", &AccessLogSettingS{RedactPatterns: tt.patterns})
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() err = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() err = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateMessages(t *testing.T) {
+	tests := []struct {
+		name    string
+		section string
+		v       any
+		want    string
+	}{
+		{"required", "JWT", &JWTSettingS{Issuer: "blog-service", Expire: time.Hour}, "invalid JWT setting: JWT.Secret is required"},
+		{"required_if", "Tracing", &TracingSettingS{Enable: true}, "invalid Tracing setting: Tracing.ServiceName is required when Enable is true"},
+		{"oneof", "Server", &ServerSettingS{RunMode: "prod", HttpPort: "8000"}, `invalid Server setting: Server.RunMode must be one of [debug release test], got "prod"`},
+		{"min duration", "JWT", &JWTSettingS{Secret: "s", Issuer: "blog-service", Expire: 500 * time.Millisecond}, "invalid JWT setting: JWT.Expire must be at least 1s, got 500ms"},
+		{"lte", "Tracing", &TracingSettingS{SampleRatio: 2}, "invalid Tracing setting: Tracing.SampleRatio must be at most 1, got 2"},
+		{"gtefield", "App", &AppSettingS{DefaultPageSize: 10, MaxPageSize: 5, LogSavePath: "logs", LogFileName: "app", UploadSavePath: "upload", UploadImageMaxSize: 5, UploadServerUrl: "http://127.0.0.1:8000/static"}, "invalid App setting: App.MaxPageSize must not be less than DefaultPageSize, got 5"},
+		{"other tags", "Server", &ServerSettingS{RunMode: "debug", HttpPort: "http"}, `invalid Server setting: Server.HttpPort must satisfy numeric, got "http"`},
+		{"nested slice field", "RateLimit", &RateLimitSettingS{Rules: []RateLimitRuleS{{Method: "GET"}, {Method: "FETCH"}}}, `invalid RateLimit setting: RateLimit.Rules[1].Method must be one of [* GET POST PUT PATCH DELETE HEAD OPTIONS], got "FETCH"`},
+		{"all fields", "JWT", &JWTSettingS{}, "invalid JWT setting: JWT.Secret is required; JWT.Issuer is required; JWT.Expire must be at least 1s, got 0s"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.section, tt.v)
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("Validate() err = %v, want %s", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestMissingSection(t *testing.T) {
+	s := newTestSetting(t)
+	//没有必填项的区段得到零值
+	var accessLog *AccessLogSettingS
+	if err := s.ReadSection("AccessLog", &accessLog); err != nil {
+		t.Fatal(err)
+	}
+	if accessLog == nil {
+		t.Fatal("missing AccessLog section decoded to nil")
+	}
+	//有必填项的区段报告缺少的字段
+	var jwt *JWTSettingS
+	err := s.ReadSection("JWT", &jwt)
+	if err == nil || !strings.Contains(err.Error(), "JWT.Secret is required") {
+		t.Errorf("ReadSection(JWT) err = %v, want JWT.Secret is required", err)
+	}
+}
+
+func TestWithUnits(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  any
+		typ  reflect.Type
+		want any
+	}{
+		{
+			"seconds",
+			map[string]any{"ReadTimeout": 60, "WriteTimeout": "30s", "HttpPort": 8000},
+			reflect.TypeOf(&ServerSettingS{}),
+			map[string]any{"ReadTimeout": "1m0s", "WriteTimeout": "30s", "HttpPort": 8000},
+		},
+		{
+			"keys match case-insensitively",
+			map[any]any{"readtimeout": 1.5},
+			reflect.TypeOf(ServerSettingS{}),
+			map[string]any{"readtimeout": "1.5s"},
+		},
+		{
+			"numeric string",
+			map[string]any{"Expire": " 7200 "},
+			reflect.TypeOf(&JWTSettingS{}),
+			map[string]any{"Expire": "2h0m0s"},
+		},
+		{
+			"nested milliseconds and slices",
+			map[string]any{
+				"Store": map[string]any{"Timeout": 500},
+				"Rules": []any{map[string]any{"FillInterval": 1}, map[string]any{"FillInterval": "100ms"}},
+			},
+			reflect.TypeOf(&RateLimitSettingS{}),
+			map[string]any{
+				"Store": map[string]any{"Timeout": "500ms"},
+				"Rules": []any{map[string]any{"FillInterval": "1s"}, map[string]any{"FillInterval": "100ms"}},
+			},
+		},
+		{"not a section", "Server", reflect.TypeOf(ServerSettingS{}), "Server"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := withUnits(tt.raw, tt.typ); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("withUnits() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadSectionUnits(t *testing.T) {
+	s := newTestSetting(t)
+	var server *ServerSettingS
+	if err := s.ReadSection("Server", &server); err != nil {
+		t.Fatal(err)
+	}
+	//配置文件中写的是 ReadTimeout: 60
+	if server.ReadTimeout != 60*time.Second {
+		t.Errorf("ReadTimeout = %v, want 60s", server.ReadTimeout)
+	}
+}
+
+func TestDump(t *testing.T) {
+	sections := struct {
+		JWT      *JWTSettingS
+		Database *DatabaseSettingS
+		Log      *LogSettingS
+	}{
+		JWT:      &JWTSettingS{Secret: "jwt-secret", Issuer: "blog-service", Expire: 2 * time.Hour},
+		Database: &DatabaseSettingS{Username: "root"},
+		Log:      &LogSettingS{Levels: map[string]string{"limiter": "info", "access": "warn"}},
+	}
+	var buf bytes.Buffer
+	if err := Dump(&buf, sections); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	if strings.Contains(out, "jwt-secret") {
+		t.Errorf("Dump() leaks JWT.Secret:\n%s", out)
+	}
+	for _, want := range []string{
+		"Secret: '" + SecretMask + "'",
+		"Expire: 2h0m0s",
+		//未配置的 secret 字段保持为空，便于看出是否已设置
+		"Password: \"\"",
+		"Username: root",
+		"Levels:\n    access: warn\n    limiter: info",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Dump() output does not contain %q:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package setting
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+type watcher struct {
+	mu          sync.Mutex
+	subscribers []func()
+}
+
+// 注册配置变化的订阅者，订阅者在同一个协程中串行执行，应自行通过 ReadSection 读取需要的区段
+func (s *Setting) Subscribe(fn func()) {
+	s.watcher.mu.Lock()
+	defer s.watcher.mu.Unlock()
+	s.watcher.subscribers = append(s.watcher.subscribers, fn)
+}
+
+// 监听配置文件变化，依次通知订阅者；环境变量在订阅者读取区段时重新应用
+func (s *Setting) WatchSettingChange() {
+	s.vp.OnConfigChange(func(in fsnotify.Event) {
+		w := s.watcher
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		for _, fn := range w.subscribers {
+			fn()
+		}
+	})
+	s.vp.WatchConfig()
+}
+
+// 比较同一区段的新旧值，返回发生变化的字段，如 App.DefaultPageSize
+func Diff(section string, old, new any) []string {
+	ov, nv := reflect.Indirect(reflect.ValueOf(old)), reflect.Indirect(reflect.ValueOf(new))
+	if !ov.IsValid() || !nv.IsValid() || ov.Kind() != reflect.Struct || ov.Type() != nv.Type() {
+		if reflect.DeepEqual(old, new) {
+			return nil
+		}
+		return []string{section}
+	}
+	var keys []string
+	for i := 0; i < ov.NumField(); i++ {
+		if !reflect.DeepEqual(ov.Field(i).Interface(), nv.Field(i).Interface()) {
+			keys = append(keys, section+"."+ov.Type().Field(i).Name)
+		}
+	}
+	return keys
+}
+
+// 将 new 中启动时已经生效、运行中不能替换的字段恢复为 old 中的值，返回其中被修改的字段
+func Keep(section string, old, new any, fields ...string) []string {
+	ov, nv := reflect.ValueOf(old).Elem(), reflect.ValueOf(new).Elem()
+	var keys []string
+	for _, name := range fields {
+		of, nf := ov.FieldByName(name), nv.FieldByName(name)
+		if !reflect.DeepEqual(of.Interface(), nf.Interface()) {
+			keys = append(keys, section+"."+name)
+		}
+		nf.Set(of)
+	}
+	return keys
+}
@@ -0,0 +1,110 @@
+package setting
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDiff(t *testing.T) {
+	old := &AppSettingS{DefaultPageSize: 10, MaxPageSize: 100, UploadImageAllowExts: []string{".jpg"}}
+	tests := []struct {
+		name string
+		old  any
+		new  any
+		want []string
+	}{
+		{"unchanged", old, &AppSettingS{DefaultPageSize: 10, MaxPageSize: 100, UploadImageAllowExts: []string{".jpg"}}, nil},
+		{"changed fields", old, &AppSettingS{DefaultPageSize: 20, MaxPageSize: 100, UploadImageAllowExts: []string{".jpg", ".png"}}, []string{"App.DefaultPageSize", "App.UploadImageAllowExts"}},
+		{"section added", (*AppSettingS)(nil), old, []string{"App"}},
+		{"slices", []LogSinkS{{Type: "file"}}, []LogSinkS{{Type: "stdout"}}, []string{"App"}},
+		{"equal slices", []LogSinkS{{Type: "file"}}, []LogSinkS{{Type: "file"}}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Diff("App", tt.old, tt.new); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Diff() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeep(t *testing.T) {
+	old := &AppSettingS{DefaultPageSize: 10, UploadSavePath: "storage/uploads", LogFileName: "app"}
+	new := &AppSettingS{DefaultPageSize: 20, UploadSavePath: "/data/uploads", LogFileName: "app"}
+
+	keys := Keep("App", old, new, "UploadSavePath", "LogFileName")
+	if want := []string{"App.UploadSavePath"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("Keep() = %q, want %q", keys, want)
+	}
+	if new.UploadSavePath != "storage/uploads" {
+		t.Errorf("UploadSavePath = %q, want the startup value", new.UploadSavePath)
+	}
+	//其余字段保留新值，仍可热加载
+	if new.DefaultPageSize != 20 {
+		t.Errorf("DefaultPageSize = %d, want 20", new.DefaultPageSize)
+	}
+	if keys := Diff("App", old, new); !reflect.DeepEqual(keys, []string{"App.DefaultPageSize"}) {
+		t.Errorf("Diff() after Keep = %q, want only App.DefaultPageSize", keys)
+	}
+}
+
+func TestWatchSettingChange(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(file, []byte(testConfig), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	s, err := NewSetting(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("BLOG_SERVER_RUNMODE", "release")
+
+	changed := make(chan *ServerSettingS, 1)
+	//文件写入可能触发多次通知，calls 需要加锁
+	var mu sync.Mutex
+	var calls []string
+	record := func(name string) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls = append(calls, name)
+	}
+	s.Subscribe(func() { record("first") })
+	s.Subscribe(func() {
+		record("second")
+		var server *ServerSettingS
+		if err := s.ReadSection("Server", &server); err != nil {
+			t.Error(err)
+		}
+		select {
+		case changed <- server:
+		default:
+		}
+	})
+	s.WatchSettingChange()
+
+	updated := []byte("Server:\n  RunMode: debug\n  HttpPort: 9000\n")
+	if err := os.WriteFile(file, updated, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case server := <-changed:
+		if server.HttpPort != "9000" {
+			t.Errorf("HttpPort = %q, want the value from the changed file", server.HttpPort)
+		}
+		//环境变量在重新读取时仍然生效
+		if server.RunMode != "release" {
+			t.Errorf("RunMode = %q, want the environment override", server.RunMode)
+		}
+		mu.Lock()
+		if len(calls) < 2 || calls[0] != "first" || calls[1] != "second" {
+			t.Errorf("subscribers ran as %q, want first then second", calls)
+		}
+		mu.Unlock()
+	case <-time.After(5 * time.Second):
+		t.Fatal("subscribers were not notified of the change")
+	}
+}
@@ -0,0 +1,85 @@
+package tracer
+
+import (
+	"context"
+	"strings"
+
+	"github.com/jinzhu/gorm"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	contextKey = "otel:context"
+	spanKey    = "otel:span"
+)
+
+// 将请求上下文绑定到 gorm 会话上，回调中据此创建 SQL span
+func WithContext(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if ctx == nil {
+		return db
+	}
+	return db.Set(contextKey, ctx)
+}
+
+// 为 gorm 的增删改查注册回调，每条 SQL 记录为一个 span
+func RegisterCallbacks(db *gorm.DB) {
+	callback := db.Callback()
+	callback.Create().Before("gorm:create").Register("otel:before_create", beforeCallback("create"))
+	callback.Create().After("gorm:create").Register("otel:after_create", afterCallback)
+	callback.Query().Before("gorm:query").Register("otel:before_query", beforeCallback("query"))
+	callback.Query().After("gorm:query").Register("otel:after_query", afterCallback)
+	callback.Update().Before("gorm:update").Register("otel:before_update", beforeCallback("update"))
+	callback.Update().After("gorm:update").Register("otel:after_update", afterCallback)
+	callback.Delete().Before("gorm:delete").Register("otel:before_delete", beforeCallback("delete"))
+	callback.Delete().After("gorm:delete").Register("otel:after_delete", afterCallback)
+	callback.RowQuery().Before("gorm:row_query").Register("otel:before_row_query", beforeCallback("row_query"))
+	callback.RowQuery().After("gorm:row_query").Register("otel:after_row_query", afterCallback)
+}
+
+func beforeCallback(operation string) func(scope *gorm.Scope) {
+	return func(scope *gorm.Scope) {
+		v, ok := scope.Get(contextKey)
+		if !ok {
+			return
+		}
+		ctx, ok := v.(context.Context)
+		if !ok {
+			return
+		}
+		table := scope.TableName()
+		_, span := Start(ctx, "gorm."+operation+" "+table,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				semconv.DBSystemMySQL,
+				semconv.DBOperationName(strings.ToUpper(operation)),
+				semconv.DBCollectionName(table),
+			),
+		)
+		scope.Set(spanKey, span)
+	}
+}
+
+func afterCallback(scope *gorm.Scope) {
+	v, ok := scope.Get(spanKey)
+	if !ok {
+		return
+	}
+	span, ok := v.(trace.Span)
+	if !ok {
+		return
+	}
+	defer span.End()
+
+	//只记录带占位符的语句，不记录参数值
+	span.SetAttributes(
+		semconv.DBQueryText(scope.SQL),
+		attribute.Int64("db.rows_affected", scope.DB().RowsAffected),
+	)
+	if err := scope.DB().Error; err != nil && !gorm.IsRecordNotFoundError(err) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
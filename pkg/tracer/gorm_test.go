@@ -0,0 +1,189 @@
+package tracer
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/mysql"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type tag struct {
+	ID   uint32 `gorm:"primary_key"`
+	Name string
+}
+
+func (tag) TableName() string { return "blog_tag" }
+
+func newTracedDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock, *tracetest.SpanRecorder) {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := gorm.Open("mysql", sqlDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.SetLogger(log.New(io.Discard, "", 0))
+	RegisterCallbacks(db)
+	t.Cleanup(func() {
+		db.Close()
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+	})
+	return db, mock, recorder
+}
+
+func attributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attrs := make(map[attribute.Key]attribute.Value)
+	for _, kv := range span.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	return attrs
+}
+
+func TestGormCallbacks(t *testing.T) {
+	tests := []struct {
+		name      string
+		expect    func(mock sqlmock.Sqlmock)
+		run       func(db *gorm.DB) error
+		wantName  string
+		wantOp    string
+		wantRows  int64
+		wantError bool
+	}{
+		{
+			name: "query",
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT \\* FROM `blog_tag`").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "Go").AddRow(2, "Rust"))
+			},
+			run: func(db *gorm.DB) error {
+				var tags []tag
+				return db.Where("name LIKE ?", "secret%").Find(&tags).Error
+			},
+			wantName: "gorm.query blog_tag",
+			wantOp:   "QUERY",
+			wantRows: 2,
+		},
+		{
+			name: "create",
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO `blog_tag`").WillReturnResult(sqlmock.NewResult(3, 1))
+				mock.ExpectCommit()
+			},
+			run:      func(db *gorm.DB) error { return db.Create(&tag{Name: "secret"}).Error },
+			wantName: "gorm.create blog_tag",
+			wantOp:   "CREATE",
+			wantRows: 1,
+		},
+		{
+			name: "record not found is not an error",
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT \\* FROM `blog_tag`").WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+			},
+			run:      func(db *gorm.DB) error { return db.First(&tag{}, 9).Error },
+			wantName: "gorm.query blog_tag",
+			wantOp:   "QUERY",
+		},
+		{
+			name: "failed update",
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE `blog_tag` SET").WillReturnError(errors.New("connection reset"))
+				mock.ExpectRollback()
+			},
+			run: func(db *gorm.DB) error {
+				return db.Model(&tag{ID: 3}).Update("name", "secret").Error
+			},
+			wantName:  "gorm.update blog_tag",
+			wantOp:    "UPDATE",
+			wantError: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, recorder := newTracedDB(t)
+			tt.expect(mock)
+
+			ctx, parent := Start(context.Background(), "GET /api/v1/tags")
+			err := tt.run(WithContext(ctx, db))
+			parent.End()
+			if (err != nil && !gorm.IsRecordNotFoundError(err)) != tt.wantError {
+				t.Fatalf("err = %v, want error %v", err, tt.wantError)
+			}
+
+			spans := recorder.Ended()
+			if len(spans) != 2 {
+				t.Fatalf("ended %d spans, want 2", len(spans))
+			}
+			span := spans[0]
+			if span.Name() != tt.wantName {
+				t.Errorf("name = %q, want %q", span.Name(), tt.wantName)
+			}
+			if span.SpanKind() != trace.SpanKindClient {
+				t.Errorf("kind = %v, want client", span.SpanKind())
+			}
+			if span.Parent().SpanID() != parent.SpanContext().SpanID() {
+				t.Error("SQL span is not a child of the request span")
+			}
+			attrs := attributes(span)
+			if got := attrs["db.operation.name"].AsString(); got != tt.wantOp {
+				t.Errorf("db.operation.name = %q, want %q", got, tt.wantOp)
+			}
+			if got := attrs["db.collection.name"].AsString(); got != "blog_tag" {
+				t.Errorf("db.collection.name = %q, want blog_tag", got)
+			}
+			if got := attrs["db.rows_affected"].AsInt64(); got != tt.wantRows {
+				t.Errorf("db.rows_affected = %d, want %d", got, tt.wantRows)
+			}
+			//只记录带占位符的语句，参数值不能出现在 span 中
+			query := attrs["db.query.text"].AsString()
+			if query == "" {
+				t.Error("db.query.text is empty")
+			}
+			if strings.Contains(query, "secret") {
+				t.Errorf("db.query.text = %q leaks the bound value", query)
+			}
+			wantCode := codes.Unset
+			if tt.wantError {
+				wantCode = codes.Error
+			}
+			if span.Status().Code != wantCode {
+				t.Errorf("status = %v, want %v", span.Status().Code, wantCode)
+			}
+		})
+	}
+}
+
+func TestGormCallbacksWithoutContext(t *testing.T) {
+	db, mock, recorder := newTracedDB(t)
+	mock.ExpectQuery("SELECT \\* FROM `blog_tag`").WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+
+	var tags []tag
+	if err := db.Find(&tags).Error; err != nil {
+		t.Fatal(err)
+	}
+	if spans := recorder.Ended(); len(spans) != 0 {
+		t.Errorf("ended %d spans without a request context, want 0", len(spans))
+	}
+}
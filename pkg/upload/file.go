@@ -15,6 +15,14 @@ type FileType int
 
 const TypeImage FileType = iota + 1
 
+func (t FileType) String() string {
+	switch t {
+	case TypeImage:
+		return "image"
+	}
+	return "unknown"
+}
+
 // 获得加密后的文件名
 func GetFileName(name string) string {
 	ext := GetFileExt(name)
@@ -29,7 +37,7 @@ func GetFileExt(name string) string {
 }
 
 func GetSavePath() string {
-	return global.AppSetting.UploadSavePath
+	return global.AppSetting.Load().UploadSavePath
 }
 
 func CheckSavePath(dst string) bool {
@@ -43,7 +51,7 @@ func CheckContainExt(t FileType, name string) bool {
 	switch t {
 	case TypeImage:
 		//自增常数
-		for _, allowExt := range global.AppSetting.UploadImageAllowExts {
+		for _, allowExt := range global.AppSetting.Load().UploadImageAllowExts {
 			if strings.ToUpper(allowExt) == ext {
 				return true
 			}
@@ -59,7 +67,7 @@ func CheckMaxSize(t FileType, f multipart.File) bool {
 	size := len(content)
 	switch t {
 	case TypeImage:
-		if size >= global.AppSetting.UploadImageMaxSize*1024*1024 {
+		if size >= global.AppSetting.Load().UploadImageMaxSize*1024*1024 {
 			return true
 		}
 	}
@@ -0,0 +1,82 @@
+package validation
+
+import (
+	"errors"
+	"io"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"golang.org/x/net/html"
+)
+
+// 小写字母和数字组成的单词，以单个连字符分隔
+var slugRegexp = regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)
+
+func slug(fl validator.FieldLevel) bool {
+	return slugRegexp.MatchString(fl.Field().String())
+}
+
+func safeURL(fl validator.FieldLevel) bool {
+	return IsSafeURL(fl.Field().String(), false)
+}
+
+// 只允许 http 和 https；relative 为 true 时还允许站内的相对地址，但不允许 //host 这种省略协议的写法，
+// 浏览器会把反斜杠当作斜杠，/\host 同样指向其他站点
+func IsSafeURL(s string, relative bool) bool {
+	s = strings.TrimSpace(s)
+	u, err := url.Parse(s)
+	if err != nil || u.User != nil {
+		return false
+	}
+	if u.Scheme == "" {
+		return relative && u.Host == "" && s != "" && !strings.HasPrefix(strings.ReplaceAll(s, "\\", "/"), "//")
+	}
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// 文章内容中允许出现的标签及其属性
+var allowedHTML = map[string][]string{
+	"a": {"href", "title"}, "img": {"src", "alt", "title"},
+	"p": nil, "br": nil, "hr": nil, "span": nil, "div": nil,
+	"h1": nil, "h2": nil, "h3": nil, "h4": nil, "h5": nil, "h6": nil,
+	"strong": nil, "b": nil, "em": nil, "i": nil, "u": nil, "s": nil, "del": nil,
+	"ul": nil, "ol": nil, "li": nil, "blockquote": nil, "code": nil, "pre": nil,
+	"table": nil, "thead": nil, "tbody": nil, "tr": nil, "th": nil, "td": nil,
+}
+
+// 链接类属性的值必须是安全的地址，防止 javascript: 等协议
+var urlAttrs = map[string]bool{"href": true, "src": true}
+
+func safeHTML(fl validator.FieldLevel) bool {
+	return IsSafeHTML(fl.Field().String())
+}
+
+// 不含 HTML 的纯文本（包括 Markdown）总是合法的
+func IsSafeHTML(s string) bool {
+	z := html.NewTokenizer(strings.NewReader(s))
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			return errors.Is(z.Err(), io.EOF)
+		case html.StartTagToken, html.SelfClosingTagToken, html.EndTagToken:
+			t := z.Token()
+			attrs, ok := allowedHTML[t.Data]
+			if !ok {
+				return false
+			}
+			for _, attr := range t.Attr {
+				if !slices.Contains(attrs, attr.Key) {
+					return false
+				}
+				if urlAttrs[attr.Key] && !IsSafeURL(attr.Val, true) {
+					return false
+				}
+			}
+		case html.CommentToken, html.DoctypeToken:
+			return false
+		}
+	}
+}
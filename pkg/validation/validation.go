@@ -0,0 +1,94 @@
+package validation
+
+import (
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	"github.com/ludyyy-lu/goBlogService/pkg/i18n"
+)
+
+// 自定义校验规则及其各语言的错误信息，信息中的 {0} 替换为字段名
+type Rule struct {
+	Tag  string
+	Func validator.Func
+	// 需要请求上下文的规则（如查询数据库）使用 FuncCtx，与 Func 二选一
+	FuncCtx validator.FuncCtx
+	// Func 和 FuncCtx 都为空时只注册错误信息，用于结构体级别校验上报的 tag
+	Messages map[string]string
+}
+
+var registered []Rule
+
+// 在启动时调用，向校验器注册规则并记录其错误信息供 RegisterTranslations 使用
+func Register(v *validator.Validate, rules ...Rule) error {
+	for _, rule := range rules {
+		switch {
+		case rule.FuncCtx != nil:
+			if err := v.RegisterValidationCtx(rule.Tag, rule.FuncCtx); err != nil {
+				return err
+			}
+		case rule.Func != nil:
+			if err := v.RegisterValidation(rule.Tag, rule.Func); err != nil {
+				return err
+			}
+		}
+		registered = append(registered, rule)
+	}
+	return nil
+}
+
+// 为翻译器注册所有自定义规则的错误信息，没有对应语言时使用默认语言
+func RegisterTranslations(v *validator.Validate, trans ut.Translator, locale string) error {
+	for _, rule := range registered {
+		msg, ok := rule.Messages[locale]
+		if !ok {
+			msg = rule.Messages[i18n.Default]
+		}
+		tag := rule.Tag
+		err := v.RegisterTranslation(tag, trans, func(ut ut.Translator) error {
+			return ut.Add(tag, msg, true)
+		}, func(ut ut.Translator, fe validator.FieldError) string {
+			t, err := ut.T(tag, fe.Field(), fe.Param())
+			if err != nil {
+				return fe.Error()
+			}
+			return t
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 与业务数据无关的通用规则
+func DefaultRules() []Rule {
+	return []Rule{
+		{
+			Tag:  "slug",
+			Func: slug,
+			Messages: map[string]string{
+				i18n.ZH:   "{0}只能包含小写字母、数字和连字符，且不能以连字符开头或结尾",
+				i18n.EN:   "{0} may only contain lowercase letters, digits and hyphens, and must not start or end with a hyphen",
+				i18n.ZHTW: "{0}只能包含小寫字母、數字和連字號，且不能以連字號開頭或結尾",
+			},
+		},
+		{
+			Tag:  "safe_url",
+			Func: safeURL,
+			Messages: map[string]string{
+				i18n.ZH:   "{0}必须是有效的 http 或 https 地址",
+				i18n.EN:   "{0} must be a valid http or https URL",
+				i18n.ZHTW: "{0}必須是有效的 http 或 https 網址",
+			},
+		},
+		{
+			Tag:  "safe_html",
+			Func: safeHTML,
+			Messages: map[string]string{
+				i18n.ZH:   "{0}包含不允许的 HTML 标签或属性",
+				i18n.EN:   "{0} contains HTML tags or attributes that are not allowed",
+				i18n.ZHTW: "{0}包含不允許的 HTML 標籤或屬性",
+			},
+		},
+	}
+}
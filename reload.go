@@ -0,0 +1,79 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/ludyyy-lu/goBlogService/global"
+	"github.com/ludyyy-lu/goBlogService/internal/routers"
+	"github.com/ludyyy-lu/goBlogService/pkg/setting"
+)
+
+// 监听配置文件，修改后热加载配置
+func watchSetting() {
+	settingLoader.Subscribe(reloadSetting)
+	settingLoader.WatchSettingChange()
+}
+
+// App（上传目录和日志文件除外）、Email、RateLimit 规则和日志级别修改后立即生效；其余配置修改后只记录日志，重启后才生效。
+// 新配置读取失败时保留当前配置。
+func reloadSetting() {
+	log := global.Logger.WithComponent("setting")
+	s, err := readSettings(settingLoader)
+	if err != nil {
+		log.Errorf("reload setting err: %v", err)
+		return
+	}
+
+	appSetting := global.AppSetting.Load()
+	emailSetting := global.EmailSetting.Load()
+	logSetting := global.LogSetting.Load()
+	rateLimitSetting := global.RateLimitSetting.Load()
+
+	var restart []string
+	restart = append(restart, setting.Diff("Server", global.ServerSetting, s.Server)...)
+	restart = append(restart, setting.Diff("Database", global.DatabaseSetting, s.Database)...)
+	restart = append(restart, setting.Diff("JWT", global.JWTSetting, s.JWT)...)
+	restart = append(restart, setting.Diff("OIDC", global.OIDCSetting, s.OIDC)...)
+	restart = append(restart, setting.Diff("AccessLog", global.AccessLogSetting, s.AccessLog)...)
+	restart = append(restart, setting.Diff("Metrics", global.MetricsSetting, s.Metrics)...)
+	restart = append(restart, setting.Diff("Tracing", global.TracingSetting, s.Tracing)...)
+	restart = append(restart, setting.Diff("Health", global.HealthSetting, s.Health)...)
+	//日志输出目标和限流存储在启动时创建，上传目录由静态文件路由在启动时绑定，日志文件在启动时打开，沿用原值
+	restart = append(restart, setting.Keep("Log", logSetting, s.Log, "Sinks")...)
+	restart = append(restart, setting.Keep("RateLimit", rateLimitSetting, s.RateLimit, "Store")...)
+	restart = append(restart, setting.Keep("App", appSetting, s.App, "UploadSavePath", "LogSavePath", "LogFileName", "LogFileExt")...)
+
+	var applied []string
+	if keys := setting.Diff("App", appSetting, s.App); len(keys) > 0 {
+		global.AppSetting.Store(s.App)
+		applied = append(applied, keys...)
+	}
+	if keys := setting.Diff("Email", emailSetting, s.Email); len(keys) > 0 {
+		global.EmailSetting.Store(s.Email)
+		applied = append(applied, keys...)
+	}
+	if keys := setting.Diff("Log", logSetting, s.Log); len(keys) > 0 {
+		if err := applyLogLevels(s.Log); err != nil {
+			log.Errorf("applyLogLevels err: %v", err)
+		} else {
+			global.LogSetting.Store(s.Log)
+			applied = append(applied, keys...)
+		}
+	}
+	if keys := setting.Diff("RateLimit", rateLimitSetting, s.RateLimit); len(keys) > 0 {
+		global.RateLimitSetting.Store(s.RateLimit)
+		if err := routers.ReloadLimiter(); err != nil {
+			global.RateLimitSetting.Store(rateLimitSetting)
+			log.Errorf("routers.ReloadLimiter err: %v", err)
+		} else {
+			applied = append(applied, keys...)
+		}
+	}
+
+	if len(applied) > 0 {
+		log.Infof("setting reloaded: %s", strings.Join(applied, ", "))
+	}
+	if len(restart) > 0 {
+		log.Warnf("setting changed but requires restart to take effect: %s", strings.Join(restart, ", "))
+	}
+}